@@ -0,0 +1,142 @@
+package simple
+
+// In adaptive mode every value is written at exactly its own width,
+// preceded by the change of width from the previous value: the change is
+// zigzag encoded and written, plus one, as an Elias-gamma code (n 0 bits, a
+// 1 bit, then the n low bits of the number). The width before the first
+// value is taken to be the full word width. Values are written in list
+// order, so lists of both orders decode front-to-back.
+func zigzag(d int) int {
+	if d < 0 {
+		return -2*d - 1
+	}
+	return 2 * d
+}
+
+func unzigzag(z int) int {
+	if z&1 != 0 {
+		return -(z + 1) / 2
+	}
+	return z / 2
+}
+
+func gammaBitsLen(x int) int {
+	return 2*bitsLen(uint32(x)) - 1
+}
+
+// maxGamma returns the largest width change code, which is the one for a
+// width growing from 1 to the full word width.
+func maxGamma[T Unsigned]() int {
+	return zigzag(wordSize[T]()-1) + 1
+}
+
+func adaptiveBitsLen[T Unsigned](values []T, base T) int {
+	bits := 0
+	w := wordSize[T]()
+	for _, v := range values {
+		vw := bitsLen(v - base)
+		bits += gammaBitsLen(zigzag(vw-w)+1) + vw
+		w = vw
+	}
+	return bits
+}
+
+func (c *GenericCompressor[T]) isAdaptive() bool {
+	return c.Adaptive && !c.Delta
+}
+
+func (d *GenericDecompressor[T]) isAdaptive() bool {
+	return d.Adaptive && !d.Delta
+}
+
+func (c *GenericCompressor[T]) writeGamma(x int) error {
+	n := bitsLen(uint32(x)) - 1
+	if n > 0 {
+		if err := c.writer.Write(0, n); err != nil {
+			return err
+		}
+	}
+	if err := c.writer.Write(1, 1); err != nil {
+		return err
+	}
+	if n > 0 {
+		return c.writer.Write(uint64(x), n)
+	}
+	return nil
+}
+
+func (c *GenericCompressor[T]) writeValuesAdaptive(values []T) error {
+	w := wordSize[T]()
+	for _, v := range values {
+		v -= c.base
+		vw := bitsLen(v)
+		if err := c.writeGamma(zigzag(vw-w) + 1); err != nil {
+			return err
+		}
+		if err := c.writer.Write(uint64(v), vw); err != nil {
+			return err
+		}
+		w = vw
+	}
+	return nil
+}
+
+func (d *GenericDecompressor[T]) readGamma() (int, error) {
+	n := 0
+	for {
+		bit, err := d.read(1)
+		if err != nil {
+			return 0, err
+		}
+		if bit == 1 {
+			break
+		}
+		n++
+		if n >= bitsLen(uint32(maxGamma[T]())) {
+			return 0, ErrCorruptInput
+		}
+	}
+
+	x := 1 << n
+	if n > 0 {
+		low, err := d.read(n)
+		if err != nil {
+			return 0, err
+		}
+		x |= int(low)
+	}
+	return x, nil
+}
+
+// readAdaptive reads the next value given the width w of the previous one,
+// and returns it along with its width.
+func (d *GenericDecompressor[T]) readAdaptive(w int) (T, int, error) {
+	x, err := d.readGamma()
+	if err != nil {
+		return 0, 0, err
+	}
+
+	w += unzigzag(x - 1)
+	if w < 1 || w > wordSize[T]() {
+		return 0, 0, ErrCorruptInput
+	}
+
+	v, err := d.read(w)
+	if err != nil {
+		return 0, 0, err
+	}
+	return T(v), w, nil
+}
+
+func (d *GenericDecompressor[T]) readValuesAdaptive(output []T) ([]T, error) {
+	w := wordSize[T]()
+	for i := range output {
+		v, vw, err := d.readAdaptive(w)
+		if err != nil {
+			return nil, err
+		}
+		output[i] = v
+		w = vw
+	}
+	return output, nil
+}
@@ -0,0 +1,146 @@
+package simple
+
+import (
+	"testing"
+
+	"github.com/vteromero/playground/simple-integer-list-compression/slice"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCompressor_CompressAdaptive(t *testing.T) {
+	params := []struct {
+		order     int
+		input     []uint32
+		expectedN int
+	}{
+		{OrderAscending, []uint32{}, 8},
+		{OrderAscending, []uint32{0}, 8 + 11 + 1},
+		{OrderAscending, []uint32{5, 111, 8888}, 8 + (11 + 3) + (7 + 7) + (7 + 14)},
+		{OrderAscending, []uint32{7, 7, 7}, 8 + (11 + 3) + 2*(1+3)},
+		{OrderAscending, []uint32{1, 0xffffffff}, 8 + (11 + 1) + (11 + 32)},
+		{OrderDescending, []uint32{8888, 111, 5}, 8 + (11 + 14) + (7 + 7) + (7 + 3)},
+	}
+
+	for _, testCase := range params {
+		c := NewCompressor(testCase.order, 8)
+		c.Adaptive = true
+		output := make([]byte, c.MaxCompressedLen(len(testCase.input)))
+		m, err := c.Compress(testCase.input, output)
+		assert.Nil(t, err)
+		assert.Equal(t, testCase.expectedN, m)
+
+		d := NewDecompressor(testCase.order, 8)
+		d.Adaptive = true
+		decompressed, err := d.Decompress(output)
+		assert.Nil(t, err)
+		assert.Equal(t, testCase.input, decompressed)
+	}
+}
+
+func TestCompressAndDecompressAdaptive(t *testing.T) {
+	params := []struct {
+		order     int
+		inputSize int
+		blockSize int
+		framed    bool
+	}{
+		{OrderAscending, 0, 0, false},
+		{OrderAscending, 10, 0, false},
+		{OrderAscending, 1000, 0, false},
+		{OrderAscending, 1000, 0, true},
+		{OrderAscending, 1000, DefaultBlockSize, false},
+		{OrderAscending, 1000, DefaultBlockSize, true},
+		{OrderDescending, 0, 0, false},
+		{OrderDescending, 1000, 0, false},
+		{OrderDescending, 1000, DefaultBlockSize, true},
+	}
+
+	for _, testCase := range params {
+		var input []uint32
+		if testCase.order == OrderAscending {
+			input = slice.SortAscUint32Slice(slice.RandomUint32Slice(testCase.inputSize))
+		} else {
+			input = slice.SortDescUint32Slice(slice.RandomUint32Slice(testCase.inputSize))
+		}
+
+		c := NewCompressor(testCase.order, 32)
+		c.Adaptive = true
+		c.BlockSize = testCase.blockSize
+		c.Framed = testCase.framed
+		compOutput := make([]byte, c.MaxCompressedLen(testCase.inputSize))
+		_, err := c.Compress(input, compOutput)
+		assert.Nil(t, err)
+
+		var d *Decompressor
+		if testCase.framed {
+			d = NewFramedDecompressor()
+		} else {
+			d = NewDecompressor(testCase.order, 32)
+			d.Adaptive = true
+			d.Blocked = testCase.blockSize > 0
+		}
+		output, err := d.Decompress(compOutput)
+		assert.Nil(t, err)
+		assert.Equal(t, input, output)
+
+		it, err := d.Iter(compOutput)
+		assert.Nil(t, err)
+		assert.Equal(t, testCase.order, it.ListOrder)
+		for _, v := range input {
+			got, ok := it.Next()
+			assert.True(t, ok)
+			assert.Equal(t, v, got)
+		}
+	}
+}
+
+func TestCompressAndDecompressAdaptive64(t *testing.T) {
+	input := slice.SortAscUint64Slice(slice.RandomUint64Slice(1000))
+
+	c := NewCompressor64(OrderAscending, 32)
+	c.Adaptive = true
+	compOutput := make([]byte, c.MaxCompressedLen(len(input)))
+	_, err := c.Compress(input, compOutput)
+	assert.Nil(t, err)
+
+	d := NewDecompressor64(OrderAscending, 32)
+	d.Adaptive = true
+	output, err := d.Decompress(compOutput)
+	assert.Nil(t, err)
+	assert.Equal(t, input, output)
+}
+
+func TestCompressAndDecompressAdaptiveUnsorted(t *testing.T) {
+	// every value carries its own width, so the order does not matter
+	input := slice.RandomUint32Slice(1000)
+
+	c := NewCompressor(OrderAscending, 32)
+	c.Adaptive = true
+	compOutput := make([]byte, c.MaxCompressedLen(len(input)))
+	_, err := c.Compress(input, compOutput)
+	assert.Nil(t, err)
+
+	d := NewDecompressor(OrderAscending, 32)
+	d.Adaptive = true
+	output, err := d.Decompress(compOutput)
+	assert.Nil(t, err)
+	assert.Equal(t, input, output)
+}
+
+func TestDecompressor_DecompressAdaptiveCorrupt(t *testing.T) {
+	params := []struct {
+		input []byte
+	}{
+		// width change code longer than any valid one
+		{[]byte{0x01, 0x00, 0x00}},
+		// width growing past 32 bits: zigzag 2, plus one, is 3
+		{[]byte{0x01, 0x06}},
+	}
+
+	for _, testCase := range params {
+		d := NewDecompressor(OrderAscending, 8)
+		d.Adaptive = true
+		_, err := d.Decompress(testCase.input)
+		assert.Equal(t, ErrCorruptInput, err)
+	}
+}
@@ -1,108 +1,209 @@
-package simple
+package simple_test
 
 import (
-	"bytes"
 	"compress/zlib"
-	"io"
+	"math/rand"
+	"sync"
 	"testing"
 
+	"github.com/vteromero/playground/simple-integer-list-compression"
+	"github.com/vteromero/playground/simple-integer-list-compression/codecs"
 	"github.com/vteromero/playground/simple-integer-list-compression/slice"
 	"github.com/dataence/encoding"
-	"github.com/dataence/encoding/bp32"
-	"github.com/dataence/encoding/composition"
 	"github.com/dataence/encoding/cursor"
-	deltabp32 "github.com/dataence/encoding/delta/bp32"
-	deltafastpfor "github.com/dataence/encoding/delta/fastpfor"
-	deltavb "github.com/dataence/encoding/delta/variablebyte"
-	"github.com/dataence/encoding/fastpfor"
-	"github.com/dataence/encoding/variablebyte"
 )
 
+// benchmarkSeed makes every run benchmark the same data.
+const benchmarkSeed = 1
+
 var (
-	sliceLen          = 10000000
-	sortedInt32Slice  = slice.SortAscInt32Slice(slice.RandomInt31Slice(sliceLen))
-	sortedUint32Slice = slice.Int32ToUint32Slice(sortedInt32Slice)
-	byteSlice         = slice.Uint32ToByteSlice(sortedUint32Slice)
+	sliceLen = 10000000
+	registry = benchmarkRegistry()
+
+	// the inputs are built on first use, so that tests and fuzzing do not
+	// pay for them
+	uint32Once        sync.Once
+	sortedUint32Slice []uint32
+	int32Once         sync.Once
+	sortedInt32Slice  []int32
+	uint64Once        sync.Once
+	sortedUint64Slice []uint64
 )
 
-func benchmarkCompressEncodingLibWithCodec(b *testing.B, codec encoding.Integer) {
+// benchmarkRegistry returns the default codecs, with zlib at the fastest
+// level, which is the one the benchmarks have always measured.
+func benchmarkRegistry() *codecs.Registry {
+	r := codecs.NewRegistry(32)
+	r.Register(codecs.Zlib(zlib.BestSpeed))
+	return r
+}
+
+// integerCodec is implemented by the codecs of github.com/dataence/encoding,
+// which are benchmarked on int32 words directly rather than through the
+// conversions of their simple.Codec adapter.
+type integerCodec interface {
+	Integer() encoding.Integer
+}
+
+func benchmarkUint32Slice() []uint32 {
+	uint32Once.Do(func() {
+		r := rand.New(rand.NewSource(benchmarkSeed))
+		sortedUint32Slice = slice.Int32ToUint32Slice(slice.SortAscInt32Slice(slice.RandomInt31SliceFrom(r, sliceLen)))
+	})
+	return sortedUint32Slice
+}
+
+func benchmarkInt32Slice() []int32 {
+	int32Once.Do(func() {
+		sortedInt32Slice = slice.Uint32ToInt32Slice(benchmarkUint32Slice())
+	})
+	return sortedInt32Slice
+}
+
+func benchmarkUint64Slice() []uint64 {
+	uint64Once.Do(func() {
+		r := rand.New(rand.NewSource(benchmarkSeed))
+		sortedUint64Slice = slice.SortAscUint64Slice(slice.RandomUint64SliceFrom(r, sliceLen))
+	})
+	return sortedUint64Slice
+}
+
+func benchmarkCompressInteger(b *testing.B, codec encoding.Integer) {
+	input := benchmarkInt32Slice()
 	out := make([]int32, sliceLen*2)
 
+	b.ReportAllocs()
 	b.ResetTimer()
 
 	for i := 0; i < b.N; i++ {
 		inpos := cursor.New()
 		outpos := cursor.New()
-		codec.Compress(sortedInt32Slice, inpos, sliceLen, out, outpos)
+		codec.Compress(input, inpos, sliceLen, out, outpos)
 	}
 }
 
-func benchmarkDecompressEncodingLibWithCodec(b *testing.B, codec encoding.Integer) {
-	compInLen := sliceLen
-	compOut := make([]int32, compInLen*2)
-	compInpos := cursor.New()
+func benchmarkDecompressInteger(b *testing.B, codec encoding.Integer) {
+	input := benchmarkInt32Slice()
+	compOut := make([]int32, sliceLen*2)
 	compOutpos := cursor.New()
-	codec.Compress(sortedInt32Slice, compInpos, compInLen, compOut, compOutpos)
+	codec.Compress(input, cursor.New(), sliceLen, compOut, compOutpos)
 	compOutLen := compOutpos.Get()
 
-	uncompInLen := compOutLen
-	uncompOut := make([]int32, sliceLen)
+	out := make([]int32, sliceLen)
 
+	b.ReportAllocs()
 	b.ResetTimer()
 
 	for i := 0; i < b.N; i++ {
-		uncompInpos := cursor.New()
-		uncompOutpos := cursor.New()
-		codec.Uncompress(compOut, uncompInpos, uncompInLen, uncompOut, uncompOutpos)
+		inpos := cursor.New()
+		outpos := cursor.New()
+		codec.Uncompress(compOut, inpos, compOutLen, out, outpos)
+	}
+}
+
+func BenchmarkCompress(b *testing.B) {
+	input := benchmarkUint32Slice()
+	for _, codec := range registry.Codecs() {
+		if ic, ok := codec.(integerCodec); ok {
+			b.Run(codec.Name(), func(b *testing.B) {
+				benchmarkCompressInteger(b, ic.Integer())
+			})
+			continue
+		}
+
+		b.Run(codec.Name(), func(b *testing.B) {
+			out := make([]byte, codec.MaxEncodedLen(sliceLen))
+
+			b.ReportAllocs()
+			b.ResetTimer()
+
+			for i := 0; i < b.N; i++ {
+				codec.Encode(input, out)
+			}
+		})
+	}
+}
+
+func BenchmarkDecompress(b *testing.B) {
+	input := benchmarkUint32Slice()
+	for _, codec := range registry.Codecs() {
+		if ic, ok := codec.(integerCodec); ok {
+			b.Run(codec.Name(), func(b *testing.B) {
+				benchmarkDecompressInteger(b, ic.Integer())
+			})
+			continue
+		}
+
+		b.Run(codec.Name(), func(b *testing.B) {
+			data := make([]byte, codec.MaxEncodedLen(sliceLen))
+			n, _ := codec.Encode(input, data)
+			data = data[:n]
+			out := make([]uint32, sliceLen)
+
+			b.ReportAllocs()
+			b.ResetTimer()
+
+			for i := 0; i < b.N; i++ {
+				codec.Decode(data, out)
+			}
+		})
 	}
 }
 
-func BenchmarkCompressSimple(b *testing.B) {
-	c := NewCompressor(OrderAscending, 32)
+func BenchmarkCompressSimpleDelta(b *testing.B) {
+	input := benchmarkUint32Slice()
+	c := simple.NewCompressor(simple.OrderAscending, 32)
+	c.Delta = true
 	out := make([]byte, c.MaxCompressedLen(sliceLen))
 
+	b.ReportAllocs()
 	b.ResetTimer()
 
 	for i := 0; i < b.N; i++ {
-		c.Compress(sortedUint32Slice, out)
+		c.Compress(input, out)
 	}
 }
 
-func BenchmarkCompressZlib(b *testing.B) {
-	var output bytes.Buffer
-	writer, _ := zlib.NewWriterLevel(&output, zlib.BestSpeed)
+func BenchmarkCompressSimple64(b *testing.B) {
+	input := benchmarkUint64Slice()
+	c := simple.NewCompressor64(simple.OrderAscending, 32)
+	out := make([]byte, c.MaxCompressedLen(sliceLen))
 
+	b.ReportAllocs()
 	b.ResetTimer()
 
 	for i := 0; i < b.N; i++ {
-		writer.Write(byteSlice)
-		writer.Close()
+		c.Compress(input, out)
 	}
 }
 
-func BenchmarkCompressBP32(b *testing.B) {
-	benchmarkCompressEncodingLibWithCodec(b, composition.New(bp32.New(), variablebyte.New()))
-}
+func BenchmarkDecompressSimpleDelta(b *testing.B) {
+	input := benchmarkUint32Slice()
+	c := simple.NewCompressor(simple.OrderAscending, 32)
+	c.Delta = true
+	data := make([]byte, c.MaxCompressedLen(sliceLen))
+	c.Compress(input, data)
 
-func BenchmarkCompressFastpfor(b *testing.B) {
-	benchmarkCompressEncodingLibWithCodec(b, composition.New(fastpfor.New(), variablebyte.New()))
-}
+	d := simple.NewDecompressor(simple.OrderAscending, 32)
+	d.Delta = true
 
-func BenchmarkCompressDeltaBP32(b *testing.B) {
-	benchmarkCompressEncodingLibWithCodec(b, composition.New(deltabp32.New(), deltavb.New()))
-}
+	b.ReportAllocs()
+	b.ResetTimer()
 
-func BenchmarkCompressDeltaFastpfor(b *testing.B) {
-	benchmarkCompressEncodingLibWithCodec(b, composition.New(deltafastpfor.New(), deltavb.New()))
+	for i := 0; i < b.N; i++ {
+		d.Decompress(data)
+	}
 }
 
-func BenchmarkDecompressSimple(b *testing.B) {
-	c := NewCompressor(OrderAscending, 32)
+func BenchmarkDecompressSimple64(b *testing.B) {
+	input := benchmarkUint64Slice()
+	c := simple.NewCompressor64(simple.OrderAscending, 32)
 	data := make([]byte, c.MaxCompressedLen(sliceLen))
-	c.Compress(sortedUint32Slice, data)
+	c.Compress(input, data)
 
-	d := NewDecompressor(OrderDescending, 32)
+	d := simple.NewDecompressor64(simple.OrderAscending, 32)
 
+	b.ReportAllocs()
 	b.ResetTimer()
 
 	for i := 0; i < b.N; i++ {
@@ -110,37 +211,37 @@ func BenchmarkDecompressSimple(b *testing.B) {
 	}
 }
 
-func BenchmarkDecompressZlib(b *testing.B) {
-	var buff bytes.Buffer
+func BenchmarkDecompressIntoSimple(b *testing.B) {
+	input := benchmarkUint32Slice()
+	c := simple.NewCompressor(simple.OrderAscending, 32)
+	data := make([]byte, c.MaxCompressedLen(sliceLen))
+	c.Compress(input, data)
 
-	writer, _ := zlib.NewWriterLevel(&buff, zlib.BestSpeed)
-	writer.Write(byteSlice)
-	writer.Close()
-	compressed := buff.Bytes()
+	d := simple.NewDecompressor(simple.OrderAscending, 32)
+	dst := make([]uint32, sliceLen)
 
+	b.ReportAllocs()
 	b.ResetTimer()
 
 	for i := 0; i < b.N; i++ {
-		bytesReader := bytes.NewReader(compressed)
-		var bytesBuff bytes.Buffer
-		reader, _ := zlib.NewReader(bytesReader)
-		io.Copy(&bytesBuff, reader)
-		reader.Close()
+		d.DecompressInto(data, dst)
 	}
 }
 
-func BenchmarkDecompressBP32(b *testing.B) {
-	benchmarkDecompressEncodingLibWithCodec(b, composition.New(bp32.New(), variablebyte.New()))
-}
+func BenchmarkIterSimple(b *testing.B) {
+	input := benchmarkUint32Slice()
+	c := simple.NewCompressor(simple.OrderAscending, 32)
+	data := make([]byte, c.MaxCompressedLen(sliceLen))
+	c.Compress(input, data)
 
-func BenchmarkDecompressFastpfor(b *testing.B) {
-	benchmarkDecompressEncodingLibWithCodec(b, composition.New(fastpfor.New(), variablebyte.New()))
-}
+	d := simple.NewDecompressor(simple.OrderAscending, 32)
 
-func BenchmarkDecompressDeltaBP32(b *testing.B) {
-	benchmarkDecompressEncodingLibWithCodec(b, composition.New(deltabp32.New(), deltavb.New()))
-}
+	b.ReportAllocs()
+	b.ResetTimer()
 
-func BenchmarkDecompressDeltaFastpfor(b *testing.B) {
-	benchmarkDecompressEncodingLibWithCodec(b, composition.New(deltafastpfor.New(), deltavb.New()))
+	for i := 0; i < b.N; i++ {
+		it, _ := d.Iter(data)
+		for _, ok := it.Next(); ok; _, ok = it.Next() {
+		}
+	}
 }
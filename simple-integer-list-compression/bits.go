@@ -2,11 +2,19 @@ package simple
 
 import "math/bits"
 
-func bitsLen(x uint32) int {
+type Unsigned interface {
+	~uint32 | ~uint64
+}
+
+func bitsLen[T Unsigned](x T) int {
 	if x == 0 {
 		return 1
 	}
-	return bits.Len32(x)
+	return bits.Len64(uint64(x))
+}
+
+func wordSize[T Unsigned]() int {
+	return bits.Len64(uint64(^T(0)))
 }
 
 func sizeInBytes(bits int) int {
@@ -0,0 +1,308 @@
+package simple
+
+import (
+	"sort"
+)
+
+// A blocked payload splits the list into blocks of BlockSize values, each
+// one encoded with its own width chain starting at full width. It is laid
+// out as:
+//
+//	cardinality | block size (16 bits) | directory | blocks
+//
+// where the directory holds, for every block, its bit offset (32 bits)
+// relative to the first block followed by its first and last values (at
+// full width each).
+const (
+	DefaultBlockSize    = 128
+	maxBlockSize        = 1<<16 - 1
+	blockSizeHeaderSize = 16
+	blockOffsetSize     = 32
+)
+
+type blockEntry[T Unsigned] struct {
+	offset int
+	first  T
+	last   T
+}
+
+func blockEntrySize[T Unsigned]() int {
+	return blockOffsetSize + 2*wordSize[T]()
+}
+
+func numBlocks(n, blockSize int) int {
+	return (n + blockSize - 1) / blockSize
+}
+
+// chainBitsLen returns the number of bits that the width chain takes to
+// encode values in the given order.
+func chainBitsLen[T Unsigned](values []T, base T, order int) int {
+	n := len(values)
+	if n == 0 {
+		return 0
+	}
+
+	bits := wordSize[T]()
+	if order == OrderAscending {
+		for i := 1; i < n; i++ {
+			bits += bitsLen(values[i] - base)
+		}
+	} else {
+		for i := 0; i < n-1; i++ {
+			bits += bitsLen(values[i] - base)
+		}
+	}
+	return bits
+}
+
+func (c *GenericCompressor[T]) isBlockSizeValid() bool {
+	return c.BlockSize >= 0 && c.BlockSize <= maxBlockSize
+}
+
+func (c *GenericCompressor[T]) blocks() [][]T {
+	blocks := make([][]T, 0, numBlocks(len(c.input), c.BlockSize))
+	for start := 0; start < len(c.input); start += c.BlockSize {
+		blocks = append(blocks, c.input[start:min(start+c.BlockSize, len(c.input))])
+	}
+	return blocks
+}
+
+func (c *GenericCompressor[T]) writeBlocks() error {
+	if err := c.writer.Write(uint64(c.BlockSize), blockSizeHeaderSize); err != nil {
+		return err
+	}
+
+	blocks := c.blocks()
+
+	offset := 0
+	for _, block := range blocks {
+		// the offset of every block must fit in its directory field
+		if uint64(offset) >= 1<<blockOffsetSize {
+			return ErrInputTooLong
+		}
+		if err := c.writer.Write(uint64(offset), blockOffsetSize); err != nil {
+			return err
+		}
+		if err := c.writer.Write(uint64(block[0]-c.base), wordSize[T]()); err != nil {
+			return err
+		}
+		if err := c.writer.Write(uint64(block[len(block)-1]-c.base), wordSize[T]()); err != nil {
+			return err
+		}
+		offset += c.encodedBitsLen(block)
+	}
+
+	for _, block := range blocks {
+		if err := c.encodeValues(block); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// GenericBlockList gives random access to a blocked list without decoding
+// it as a whole. Only the blocks that are accessed get decoded.
+type GenericBlockList[T Unsigned] struct {
+	ListOrder    int
+	decoder      GenericDecompressor[T]
+	cardinality  int
+	blockSize    int
+	input        []byte
+	blocksOffset int
+	directory    []blockEntry[T]
+	block        []T
+	blockIndex   int
+}
+
+type BlockList = GenericBlockList[uint32]
+
+type BlockList64 = GenericBlockList[uint64]
+
+func (d *GenericDecompressor[T]) readBlockDirectory() (*GenericBlockList[T], error) {
+	blockSize, err := d.read(blockSizeHeaderSize)
+	if err != nil {
+		return nil, err
+	}
+	if blockSize == 0 {
+		return nil, ErrCorruptInput
+	}
+
+	l := &GenericBlockList[T]{
+		ListOrder:   d.ListOrder,
+		decoder:     *d,
+		cardinality: d.cardinality,
+		blockSize:   int(blockSize),
+		input:       d.input,
+		blockIndex:  -1,
+	}
+
+	n := numBlocks(l.cardinality, l.blockSize)
+	l.blocksOffset = d.headerSize() + blockSizeHeaderSize + n*blockEntrySize[T]()
+	if l.blocksOffset > 8*len(d.input) {
+		return nil, ErrTruncatedInput
+	}
+
+	l.directory = make([]blockEntry[T], n)
+	for i := range l.directory {
+		offset, err := d.read(blockOffsetSize)
+		if err != nil {
+			return nil, err
+		}
+		first, err := d.read(wordSize[T]())
+		if err != nil {
+			return nil, err
+		}
+		last, err := d.read(wordSize[T]())
+		if err != nil {
+			return nil, err
+		}
+		if i > 0 && int(offset) <= l.directory[i-1].offset {
+			return nil, ErrCorruptInput
+		}
+		l.directory[i] = blockEntry[T]{int(offset), T(first) + d.base, T(last) + d.base}
+	}
+
+	return l, nil
+}
+
+func (d *GenericDecompressor[T]) readBlocks(output []T) ([]T, error) {
+	l, err := d.readBlockDirectory()
+	if err != nil {
+		return nil, err
+	}
+
+	d.offset = l.blocksOffset
+	for i := range l.directory {
+		start := i * l.blockSize
+		end, err := l.decodeBlock(i, output[start:min(start+l.blockSize, l.cardinality)])
+		if err != nil {
+			return nil, err
+		}
+		d.offset = end
+	}
+
+	return output, nil
+}
+
+// Open parses the header and block directory of a blocked list and returns
+// a view over it.
+func (d *GenericDecompressor[T]) Open(input []byte) (*GenericBlockList[T], error) {
+	if err := d.begin(input); err != nil {
+		return nil, err
+	}
+
+	if !d.Blocked {
+		return nil, ErrNotBlocked
+	}
+
+	l, err := d.readBlockDirectory()
+	if err != nil {
+		return nil, err
+	}
+
+	if d.Checksum {
+		if err := l.verifyChecksum(); err != nil {
+			return nil, err
+		}
+	}
+
+	return l, nil
+}
+
+// decodeBlock decodes the i-th block into output and returns the bit
+// offset where the block ends.
+func (l *GenericBlockList[T]) decodeBlock(i int, output []T) (int, error) {
+	d := l.decoder
+	if err := d.seek(l.blocksOffset + l.directory[i].offset); err != nil {
+		return 0, err
+	}
+
+	if _, err := d.decodeValues(output); err != nil {
+		return 0, err
+	}
+	d.addBase(output)
+
+	return d.offset, nil
+}
+
+func (l *GenericBlockList[T]) verifyChecksum() error {
+	end := l.blocksOffset
+	if n := len(l.directory); n > 0 {
+		var err error
+		last := make([]T, l.cardinality-(n-1)*l.blockSize)
+		if end, err = l.decodeBlock(n-1, last); err != nil {
+			return err
+		}
+	}
+	return verifyChecksum(l.input, end)
+}
+
+func (l *GenericBlockList[T]) loadBlock(i int) error {
+	if l.blockIndex == i {
+		return nil
+	}
+
+	start := i * l.blockSize
+	n := min(l.blockSize, l.cardinality-start)
+	if cap(l.block) < n {
+		l.block = make([]T, n)
+	}
+	l.block = l.block[:n]
+
+	if _, err := l.decodeBlock(i, l.block); err != nil {
+		l.blockIndex = -1
+		return err
+	}
+
+	l.blockIndex = i
+	return nil
+}
+
+func (l *GenericBlockList[T]) Len() int {
+	return l.cardinality
+}
+
+// Get returns the value at position i, decoding only the block that holds
+// it.
+func (l *GenericBlockList[T]) Get(i int) (T, error) {
+	if i < 0 || i >= l.cardinality {
+		return 0, ErrIndexOutOfRange
+	}
+
+	if err := l.loadBlock(i / l.blockSize); err != nil {
+		return 0, err
+	}
+
+	return l.block[i%l.blockSize], nil
+}
+
+func (l *GenericBlockList[T]) follows(a, b T) bool {
+	if l.ListOrder == OrderAscending {
+		return a >= b
+	}
+	return a <= b
+}
+
+// Seek returns the position of the first value that is not before value in
+// the list order, that is, the first value >= value for ascending lists and
+// the first value <= value for descending ones. If there is no such value,
+// it returns Len().
+func (l *GenericBlockList[T]) Seek(value T) (int, error) {
+	i := sort.Search(len(l.directory), func(i int) bool {
+		return l.follows(l.directory[i].last, value)
+	})
+	if i == len(l.directory) {
+		return l.cardinality, nil
+	}
+
+	if err := l.loadBlock(i); err != nil {
+		return 0, err
+	}
+
+	j := sort.Search(len(l.block), func(j int) bool {
+		return l.follows(l.block[j], value)
+	})
+
+	return i*l.blockSize + j, nil
+}
@@ -0,0 +1,108 @@
+package simple
+
+import (
+	"math/rand"
+	"sort"
+	"testing"
+
+	"github.com/vteromero/playground/simple-integer-list-compression/slice"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCompressAndDecompressBlocked(t *testing.T) {
+	params := []struct {
+		order     int
+		blockSize int
+		inputSize int
+		framed    bool
+	}{
+		{OrderAscending, 1, 10, false},
+		{OrderAscending, DefaultBlockSize, 0, false},
+		{OrderAscending, DefaultBlockSize, 100, false},
+		{OrderAscending, DefaultBlockSize, 1000, false},
+		{OrderAscending, DefaultBlockSize, 1000, true},
+		{OrderAscending, 1000, 1000, false},
+		{OrderDescending, 1, 10, false},
+		{OrderDescending, DefaultBlockSize, 0, false},
+		{OrderDescending, DefaultBlockSize, 100, false},
+		{OrderDescending, DefaultBlockSize, 1000, false},
+		{OrderDescending, DefaultBlockSize, 1000, true},
+		{OrderDescending, 1000, 1000, false},
+	}
+
+	for _, testCase := range params {
+		var input []uint32
+		if testCase.order == OrderAscending {
+			input = slice.SortAscUint32Slice(slice.RandomUint32Slice(testCase.inputSize))
+		} else {
+			input = slice.SortDescUint32Slice(slice.RandomUint32Slice(testCase.inputSize))
+		}
+
+		c := NewCompressor(testCase.order, 32)
+		c.BlockSize = testCase.blockSize
+		c.Framed = testCase.framed
+		compOutput := make([]byte, c.MaxCompressedLen(testCase.inputSize))
+		_, err := c.Compress(input, compOutput)
+		assert.Nil(t, err)
+
+		d := NewDecompressor(testCase.order, 32)
+		d.Blocked = true
+		d.Framed = testCase.framed
+		output, err := d.Decompress(compOutput)
+		assert.Nil(t, err)
+		assert.Equal(t, input, output)
+	}
+}
+
+func TestCompressor_CompressBlockSizeOutOfBound(t *testing.T) {
+	c := NewCompressor(OrderAscending, 32)
+	c.BlockSize = maxBlockSize + 1
+	_, err := c.Compress([]uint32{1, 2, 3}, make([]byte, 64))
+	assert.Equal(t, ErrBlockSizeOutOfBound, err)
+	assert.Equal(t, 0, c.MaxCompressedLen(3))
+}
+
+func TestBlockList_GetAndSeek(t *testing.T) {
+	for _, order := range []int{OrderAscending, OrderDescending} {
+		input := slice.SortAscUint32Slice(slice.RandomUint32Slice(1000))
+		less := func(a, b uint32) bool { return a < b }
+		if order == OrderDescending {
+			input = slice.SortDescUint32Slice(input)
+			less = func(a, b uint32) bool { return a > b }
+		}
+
+		c := NewCompressor(order, 32)
+		c.BlockSize = DefaultBlockSize
+		compOutput := make([]byte, c.MaxCompressedLen(len(input)))
+		_, err := c.Compress(input, compOutput)
+		assert.Nil(t, err)
+
+		d := NewDecompressor(order, 32)
+		l, err := d.Open(compOutput)
+		assert.Equal(t, ErrNotBlocked, err)
+
+		d.Blocked = true
+		l, err = d.Open(compOutput)
+		assert.Nil(t, err)
+		assert.Equal(t, len(input), l.Len())
+
+		for _, i := range rand.Perm(len(input)) {
+			v, err := l.Get(i)
+			assert.Nil(t, err)
+			assert.Equal(t, input[i], v)
+		}
+
+		_, err = l.Get(len(input))
+		assert.Equal(t, ErrIndexOutOfRange, err)
+
+		targets := append(slice.RandomUint32Slice(100), input[0], input[500], input[len(input)-1])
+		for _, target := range targets {
+			expected := sort.Search(len(input), func(i int) bool {
+				return !less(input[i], target)
+			})
+			i, err := l.Seek(target)
+			assert.Nil(t, err)
+			assert.Equal(t, expected, i)
+		}
+	}
+}
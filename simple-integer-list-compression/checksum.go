@@ -0,0 +1,35 @@
+package simple
+
+import (
+	"encoding/binary"
+	"hash/crc32"
+)
+
+// With the Checksum option the encoded list is padded to a byte boundary
+// and followed by the CRC-32 of all the preceding bytes (4 bytes, LE).
+const checksumLen = 4
+
+func checksum(payload []byte) uint32 {
+	return crc32.ChecksumIEEE(payload)
+}
+
+func (c *GenericCompressor[T]) writeChecksum(output []byte) error {
+	n := c.writer.Offset()
+	if pad := 8*sizeInBytes(n) - n; pad > 0 {
+		if err := c.writer.Write(0, pad); err != nil {
+			return err
+		}
+	}
+	return c.writer.Write(uint64(checksum(output[:sizeInBytes(n)])), 8*checksumLen)
+}
+
+func verifyChecksum(input []byte, end int) error {
+	n := sizeInBytes(end)
+	if len(input)-n < checksumLen {
+		return ErrTruncatedInput
+	}
+	if binary.LittleEndian.Uint32(input[n:]) != checksum(input[:n]) {
+		return ErrChecksumMismatch
+	}
+	return nil
+}
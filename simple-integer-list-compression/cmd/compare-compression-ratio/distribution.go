@@ -0,0 +1,119 @@
+package main
+
+import (
+	"math"
+	"math/rand"
+	"strconv"
+	"strings"
+
+	"github.com/vteromero/playground/simple-integer-list-compression/slice"
+)
+
+// Distributions available through -distribution. Apart from uniform, they
+// use fixed parameters meant to resemble posting lists.
+const (
+	distributionUniform   = "uniform"
+	distributionZipf      = "zipf"
+	distributionClustered = "clustered"
+	distributionDense     = "dense"
+	distributionGeometric = "geometric"
+	distributionMarkov    = "markov"
+)
+
+var distributions = []string{
+	distributionUniform,
+	distributionZipf,
+	distributionClustered,
+	distributionDense,
+	distributionGeometric,
+	distributionMarkov,
+}
+
+const (
+	zipfExponent       = 1.1
+	clusterSize        = 64
+	clusterSpread      = 1024
+	denseRangeDensity  = 0.25
+	geometricMeanGap   = 1000
+	markovDenseGap     = 4
+	markovSparseGap    = 100000
+	markovSwitchChance = 0.05
+)
+
+func isDistributionValid(distribution string) bool {
+	for _, d := range distributions {
+		if d == distribution {
+			return true
+		}
+	}
+	return false
+}
+
+// distribution returns the distribution the lists were generated with, or
+// the empty string if they were read from paths.
+func distribution(paths []string, name string) string {
+	if len(paths) > 0 {
+		return ""
+	}
+	return name
+}
+
+func makeDistributionSlice(r *rand.Rand, distribution string, n int) []uint32 {
+	switch distribution {
+	case distributionZipf:
+		return slice.ZipfUint32Slice(r, n, zipfExponent, 1<<31-1)
+	case distributionClustered:
+		return slice.ClusteredUint32Slice(r, n, clusterSize, clusterSpread)
+	case distributionDense:
+		return slice.DenseRangeUint32Slice(r, n, denseRangeDensity)
+	case distributionGeometric:
+		return slice.GeometricGapUint32Slice(r, n, geometricMeanGap)
+	case distributionMarkov:
+		return slice.MarkovGapUint32Slice(r, n, markovDenseGap, markovSparseGap, markovSwitchChance)
+	}
+	return nil
+}
+
+// parseDensities parses the comma-separated -density values, skipping those
+// outside (0, 1].
+func parseDensities(str string) []float64 {
+	strDensities := strings.Split(str, ",")
+	densities := make([]float64, 0, len(strDensities))
+	for _, density := range strDensities {
+		if f, err := strconv.ParseFloat(density, 64); err == nil && f > 0 && f <= 1 {
+			densities = append(densities, f)
+		}
+	}
+	return densities
+}
+
+// densityUniverse returns the size of the universe that n values are drawn
+// from so that they fill the given fraction of it.
+func densityUniverse(n int, density float64) uint64 {
+	return uint64(math.Ceil(float64(n) / density))
+}
+
+func isDensityValid(sizes []int, densities []float64) bool {
+	for _, n := range sizes {
+		for _, density := range densities {
+			if densityUniverse(n, density) > 1<<32 {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// makeDensitySlices returns a set of every size at every density, along with
+// the density each one was drawn at.
+func makeDensitySlices(r *rand.Rand, sizes []int, densities []float64) ([][]uint32, []float64) {
+	slices := make([][]uint32, 0, len(sizes)*len(densities))
+	listDensities := make([]float64, 0, len(sizes)*len(densities))
+	for _, n := range sizes {
+		for _, density := range densities {
+			slices = append(slices, slice.RandomSortedSet(r, n, densityUniverse(n, density)))
+			listDensities = append(listDensities, density)
+		}
+	}
+	return slices, listDensities
+}
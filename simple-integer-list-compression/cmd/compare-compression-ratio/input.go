@@ -0,0 +1,164 @@
+package main
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/vteromero/playground/simple-integer-list-compression/slice"
+)
+
+// Input files hold lists in one of these formats:
+//
+//   - text: one integer per line, making up a single list.
+//   - binary: a single list of 4-byte little-endian integers, as written by
+//     slice.Uint32ToByteSlice.
+//   - lists: one list per line, its integers separated by spaces or commas.
+const (
+	inputText   = "text"
+	inputBinary = "binary"
+	inputLists  = "lists"
+)
+
+var errOddBinaryLength = errors.New("length is not a multiple of 4 bytes")
+
+func isInputFormatValid(format string) bool {
+	switch format {
+	case inputText, inputBinary, inputLists:
+		return true
+	}
+	return false
+}
+
+// inputFormat returns format if there are input files, or the empty string
+// if the lists are generated.
+func inputFormat(paths []string, format string) string {
+	if len(paths) == 0 {
+		return ""
+	}
+	return format
+}
+
+func parseUint32(field string) (uint32, error) {
+	v, err := strconv.ParseUint(field, 10, 32)
+	return uint32(v), err
+}
+
+// readLines calls f with every line of r, without its line terminator and
+// along with its number.
+func readLines(r io.Reader, f func(line string, number int) error) error {
+	br := bufio.NewReader(r)
+	for number := 1; ; number++ {
+		line, err := br.ReadString('\n')
+		if len(line) > 0 {
+			if err := f(strings.TrimRight(line, "\r\n"), number); err != nil {
+				return err
+			}
+		}
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+	}
+}
+
+func parseTextList(r io.Reader) ([]uint32, error) {
+	list := []uint32{}
+	err := readLines(r, func(line string, number int) error {
+		line = strings.TrimSpace(line)
+		if line == "" {
+			return nil
+		}
+		v, err := parseUint32(line)
+		if err != nil {
+			return fmt.Errorf("line %d: %w", number, err)
+		}
+		list = append(list, v)
+		return nil
+	})
+	return list, err
+}
+
+func parseLists(r io.Reader) ([][]uint32, error) {
+	var lists [][]uint32
+	err := readLines(r, func(line string, number int) error {
+		fields := strings.FieldsFunc(line, func(c rune) bool {
+			return c == ',' || c == ' ' || c == '\t'
+		})
+		if len(fields) == 0 {
+			return nil
+		}
+		list := make([]uint32, len(fields))
+		for i, field := range fields {
+			v, err := parseUint32(field)
+			if err != nil {
+				return fmt.Errorf("line %d: %w", number, err)
+			}
+			list[i] = v
+		}
+		lists = append(lists, list)
+		return nil
+	})
+	return lists, err
+}
+
+func parseBinaryList(r io.Reader) ([]uint32, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	if len(data)%4 != 0 {
+		return nil, errOddBinaryLength
+	}
+	return slice.ByteToUint32Slice(data), nil
+}
+
+func readInput(path, format string) ([][]uint32, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var lists [][]uint32
+	switch format {
+	case inputBinary:
+		var list []uint32
+		list, err = parseBinaryList(f)
+		lists = [][]uint32{list}
+	case inputLists:
+		lists, err = parseLists(f)
+	default:
+		var list []uint32
+		list, err = parseTextList(f)
+		lists = [][]uint32{list}
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return lists, nil
+}
+
+// loadInputs reads the lists held in every file of paths, sorted in
+// ascending order as the codecs expect.
+func loadInputs(paths []string, format string) ([][]uint32, error) {
+	var lists [][]uint32
+	for _, path := range paths {
+		l, err := readInput(path, format)
+		if err != nil {
+			return nil, err
+		}
+		lists = append(lists, l...)
+	}
+
+	for _, list := range lists {
+		slice.SortAscUint32Slice(list)
+	}
+	return lists, nil
+}
@@ -0,0 +1,150 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseTextList(t *testing.T) {
+	params := []struct {
+		input       string
+		expected    []uint32
+		expectedErr error
+	}{
+		{"", []uint32{}, nil},
+		{"5\n111\n8888\n", []uint32{5, 111, 8888}, nil},
+		{"5\n111\n8888", []uint32{5, 111, 8888}, nil},
+		{"5\r\n111\r\n8888\r\n", []uint32{5, 111, 8888}, nil},
+		{"\n5\n\n  111 \n\r\n8888\n\n", []uint32{5, 111, 8888}, nil},
+		{"4294967295\n", []uint32{4294967295}, nil},
+		{"5\n4294967296\n", nil, strconv.ErrRange},
+		{"5\n-1\n", nil, strconv.ErrSyntax},
+		{"5\nfive\n", nil, strconv.ErrSyntax},
+		{"5 111\n", nil, strconv.ErrSyntax},
+	}
+
+	for _, testCase := range params {
+		list, err := parseTextList(strings.NewReader(testCase.input))
+		if testCase.expectedErr != nil {
+			assert.ErrorIs(t, err, testCase.expectedErr, testCase.input)
+			continue
+		}
+		assert.Nil(t, err, testCase.input)
+		assert.Equal(t, testCase.expected, list, testCase.input)
+	}
+}
+
+func TestParseTextList_ErrorLine(t *testing.T) {
+	_, err := parseTextList(strings.NewReader("5\n\n4294967296\n"))
+	assert.ErrorContains(t, err, "line 3")
+}
+
+func TestParseLists(t *testing.T) {
+	params := []struct {
+		input       string
+		expected    [][]uint32
+		expectedErr error
+	}{
+		{"", nil, nil},
+		{"1 2 3\n4,5\n", [][]uint32{{1, 2, 3}, {4, 5}}, nil},
+		{"1 2 3\n4,5", [][]uint32{{1, 2, 3}, {4, 5}}, nil},
+		{"1 2 3\r\n4,5\r\n", [][]uint32{{1, 2, 3}, {4, 5}}, nil},
+		{"1, 2,\t3\n\n \n\r\n4\n", [][]uint32{{1, 2, 3}, {4}}, nil},
+		{"4294967295 0\n", [][]uint32{{4294967295, 0}}, nil},
+		{"1 2\n3 4294967296\n", nil, strconv.ErrRange},
+		{"1 2\n3 x\n", nil, strconv.ErrSyntax},
+		{"1;2\n", nil, strconv.ErrSyntax},
+	}
+
+	for _, testCase := range params {
+		lists, err := parseLists(strings.NewReader(testCase.input))
+		if testCase.expectedErr != nil {
+			assert.ErrorIs(t, err, testCase.expectedErr, testCase.input)
+			continue
+		}
+		assert.Nil(t, err, testCase.input)
+		assert.Equal(t, testCase.expected, lists, testCase.input)
+	}
+}
+
+func TestParseLists_ErrorLine(t *testing.T) {
+	_, err := parseLists(strings.NewReader("1 2\n\n3 x\n"))
+	assert.ErrorContains(t, err, "line 3")
+}
+
+func TestParseBinaryList(t *testing.T) {
+	params := []struct {
+		input       []byte
+		expected    []uint32
+		expectedErr error
+	}{
+		{[]byte{}, []uint32{}, nil},
+		{[]byte{0x05, 0x00, 0x00, 0x00, 0xb8, 0x22, 0x00, 0x00}, []uint32{5, 8888}, nil},
+		{[]byte{0xff, 0xff, 0xff, 0xff}, []uint32{4294967295}, nil},
+		{[]byte{0x05, 0x00, 0x00}, nil, errOddBinaryLength},
+		{[]byte{0x05, 0x00, 0x00, 0x00, 0xb8}, nil, errOddBinaryLength},
+	}
+
+	for _, testCase := range params {
+		list, err := parseBinaryList(strings.NewReader(string(testCase.input)))
+		assert.Equal(t, testCase.expectedErr, err)
+		if testCase.expectedErr == nil {
+			assert.Equal(t, len(testCase.expected), len(list))
+			if len(list) > 0 {
+				assert.Equal(t, testCase.expected, list)
+			}
+		}
+	}
+}
+
+func writeInputFile(t *testing.T, name string, data []byte) string {
+	path := filepath.Join(t.TempDir(), name)
+	assert.Nil(t, os.WriteFile(path, data, 0o644))
+	return path
+}
+
+func TestLoadInputs(t *testing.T) {
+	text := writeInputFile(t, "list.txt", []byte("8888\n5\r\n111\n"))
+	binary := writeInputFile(t, "list.bin", []byte{0xb8, 0x22, 0x00, 0x00, 0x05, 0x00, 0x00, 0x00})
+	lists := writeInputFile(t, "lists.txt", []byte("3 1 2\n\n9,7\n"))
+	badText := writeInputFile(t, "bad.txt", []byte("1\n99999999999\n"))
+	badBinary := writeInputFile(t, "bad.bin", []byte{0x01, 0x02})
+
+	params := []struct {
+		paths    []string
+		format   string
+		expected [][]uint32
+		hasErr   bool
+	}{
+		{[]string{text}, inputText, [][]uint32{{5, 111, 8888}}, false},
+		{[]string{binary}, inputBinary, [][]uint32{{5, 8888}}, false},
+		{[]string{lists}, inputLists, [][]uint32{{1, 2, 3}, {7, 9}}, false},
+		{[]string{text, lists}, inputLists, [][]uint32{{8888}, {5}, {111}, {1, 2, 3}, {7, 9}}, false},
+		{[]string{badText}, inputText, nil, true},
+		{[]string{badBinary}, inputBinary, nil, true},
+		{[]string{filepath.Join(t.TempDir(), "missing")}, inputText, nil, true},
+	}
+
+	for _, testCase := range params {
+		got, err := loadInputs(testCase.paths, testCase.format)
+		if testCase.hasErr {
+			assert.NotNil(t, err)
+			assert.Nil(t, got)
+			continue
+		}
+		assert.Nil(t, err)
+		assert.Equal(t, testCase.expected, got)
+	}
+}
+
+func TestLoadInputs_ErrorNamesFile(t *testing.T) {
+	path := writeInputFile(t, "bad.bin", []byte{0x01, 0x02})
+	_, err := loadInputs([]string{path}, inputBinary)
+	assert.ErrorIs(t, err, errOddBinaryLength)
+	assert.ErrorContains(t, err, path)
+}
@@ -1,94 +1,107 @@
 package main
 
 import (
-	"bytes"
-	"compress/zlib"
+	"errors"
 	"flag"
 	"fmt"
 	"log"
+	"math/rand"
 	"os"
+	"slices"
 	"strconv"
 	"strings"
-	"text/tabwriter"
+	"time"
 
 	"github.com/vteromero/playground/simple-integer-list-compression"
+	"github.com/vteromero/playground/simple-integer-list-compression/codecs"
 	"github.com/vteromero/playground/simple-integer-list-compression/slice"
-	"github.com/dataence/encoding"
-	"github.com/dataence/encoding/bp32"
-	"github.com/dataence/encoding/composition"
-	"github.com/dataence/encoding/cursor"
-	deltabp32 "github.com/dataence/encoding/delta/bp32"
-	deltafastpfor "github.com/dataence/encoding/delta/fastpfor"
-	deltavb "github.com/dataence/encoding/delta/variablebyte"
-	"github.com/dataence/encoding/fastpfor"
-	"github.com/dataence/encoding/variablebyte"
 )
 
-type compressor struct {
-	name     string
-	compress func([]int32) int
+var errMismatch = errors.New("decoded values differ from the input")
+
+var speedColumns = []string{"enc MB/s", "enc ns/int", "dec MB/s", "dec ns/int"}
+
+type tableOptions struct {
+	ratio      bool
+	verify     bool
+	speed      bool
+	iterations int
 }
 
-func simpleCompress(cardHeaderSize int) func([]int32) int {
-	return func(data []int32) int {
-		compressor := simple.NewCompressor(simple.OrderAscending, cardHeaderSize)
-		in := slice.Int32ToUint32Slice(data)
-		out := make([]byte, compressor.MaxCompressedLen(len(in)))
-		numBits, err := compressor.Compress(in, out)
-		if err != nil {
-			panic(err)
-		}
-		numBytes := numBits / 8
-		if numBits%8 > 0 {
-			numBytes++
-		}
-		return numBytes
-	}
+// measurement holds the output size of a codec for some input and, when
+// speed is measured, the average time taken to encode and decode it.
+type measurement struct {
+	outputLen  int
+	encodeTime time.Duration
+	decodeTime time.Duration
 }
 
-func zlibCompress(data []int32) int {
-	var out bytes.Buffer
-	in := slice.Int32ToByteSlice(data)
-	writer, err := zlib.NewWriterLevel(&out, zlib.BestCompression)
+func encode(codec simple.Codec, data []uint32) ([]byte, error) {
+	out := make([]byte, codec.MaxEncodedLen(len(data)))
+	n, err := codec.Encode(data, out)
 	if err != nil {
-		panic(err)
+		return nil, err
 	}
-	writer.Write(in)
-	writer.Close()
-	return out.Len()
+	return out[:n], nil
 }
 
-func compressWith(codec encoding.Integer, data []int32) int {
-	inlen := len(data)
-	out := make([]int32, inlen*2)
-	inpos := cursor.New()
-	outpos := cursor.New()
-	codec.Compress(data, inpos, inlen, out, outpos)
-	return outpos.Get() * 4
+func verify(codec simple.Codec, data []uint32, encoded []byte) error {
+	decoded, err := codec.Decode(encoded, make([]uint32, len(data)))
+	if err != nil {
+		return err
+	}
+	if !slices.Equal(decoded, data) {
+		return errMismatch
+	}
+	return nil
 }
 
-func bp32Compress(data []int32) int {
-	return compressWith(composition.New(bp32.New(), variablebyte.New()), data)
+func timeIterations(n int, f func() error) (time.Duration, error) {
+	start := time.Now()
+	for i := 0; i < n; i++ {
+		if err := f(); err != nil {
+			return 0, err
+		}
+	}
+	return time.Since(start) / time.Duration(n), nil
 }
 
-func deltaBp32Compress(data []int32) int {
-	return compressWith(composition.New(deltabp32.New(), deltavb.New()), data)
-}
+func measure(codec simple.Codec, data []uint32, opts tableOptions) (measurement, error) {
+	var m measurement
 
-func fastpforCompress(data []int32) int {
-	return compressWith(composition.New(fastpfor.New(), variablebyte.New()), data)
-}
+	encoded, err := encode(codec, data)
+	if err != nil {
+		return m, err
+	}
+	m.outputLen = len(encoded)
 
-func deltaFastpforCompress(data []int32) int {
-	return compressWith(composition.New(deltafastpfor.New(), deltavb.New()), data)
-}
+	if opts.verify {
+		if err := verify(codec, data, encoded); err != nil {
+			return m, err
+		}
+	}
 
-func sizeFunc(data []int32) int {
-	return len(data)
-}
+	if opts.speed {
+		out := make([]byte, codec.MaxEncodedLen(len(data)))
+		m.encodeTime, err = timeIterations(opts.iterations, func() error {
+			_, err := codec.Encode(data, out)
+			return err
+		})
+		if err != nil {
+			return m, err
+		}
 
-func sizeInBytesFunc(data []int32) int {
-	return 4 * len(data)
+		decoded := make([]uint32, len(data))
+		m.decodeTime, err = timeIterations(opts.iterations, func() error {
+			_, err := codec.Decode(encoded, decoded)
+			return err
+		})
+		if err != nil {
+			return m, err
+		}
+	}
+
+	return m, nil
 }
 
 func parseSizes(str string) []int {
@@ -106,10 +119,30 @@ func isCardinalityHeaderSizeValid(sz int) bool {
 	return sz >= 1 && sz <= 32
 }
 
-func makeRandomSlices(sizes []int) [][]int32 {
-	slices := make([][]int32, len(sizes))
-	for i, size := range sizes {
-		slices[i] = slice.SortAscInt32Slice(slice.RandomInt31Slice(size))
+func isRangeValid(offset, size uint64) bool {
+	return offset <= 1<<32 && size <= 1<<32-offset
+}
+
+func randomRangeSlice(r *rand.Rand, n int, offset, size uint64) []uint32 {
+	s := make([]uint32, n)
+	for i := 0; i < n; i++ {
+		s[i] = uint32(offset + uint64(r.Int63n(int64(size))))
+	}
+	return s
+}
+
+func makeRandomSlices(r *rand.Rand, distribution string, sizes []int, offset, size uint64) [][]uint32 {
+	slices := make([][]uint32, len(sizes))
+	for i, n := range sizes {
+		if distribution != distributionUniform {
+			slices[i] = makeDistributionSlice(r, distribution, n)
+			continue
+		}
+		if size == 0 {
+			slices[i] = slice.SortAscUint32Slice(slice.Int32ToUint32Slice(slice.RandomInt31SliceFrom(r, n)))
+			continue
+		}
+		slices[i] = slice.SortAscUint32Slice(randomRangeSlice(r, n, offset, size))
 	}
 	return slices
 }
@@ -125,55 +158,147 @@ func outputSizeString(out int) string {
 	return strconv.Itoa(out)
 }
 
-func makeTable(compressors []compressor, inputData [][]int32, headerRows int, showRatio bool) [][]string {
-	tableRows := len(compressors)
-	tableColumns := 1 + len(inputData)
-	table := make([][]string, tableRows)
+// inputString labels an input by its size, followed by the density it was
+// drawn at, if any.
+func inputString(n int, density float64) string {
+	if density == 0 {
+		return strconv.Itoa(n)
+	}
+	return fmt.Sprintf("%d@%g", n, density)
+}
+
+func throughputString(in int, d time.Duration) string {
+	if in == 0 {
+		return "-"
+	}
+	if d <= 0 {
+		return "inf"
+	}
+	return fmt.Sprintf("%.1f", float64(in)/d.Seconds()/1e6)
+}
+
+func nsPerIntString(n int, d time.Duration) string {
+	if n == 0 {
+		return "-"
+	}
+	return fmt.Sprintf("%.2f", float64(d.Nanoseconds())/float64(n))
+}
 
-	for i := 0; i < tableRows; i++ {
-		table[i] = make([]string, tableColumns)
-		table[i][0] = compressors[i].name
+func columnsPerInput(opts tableOptions) int {
+	if opts.speed {
+		return 1 + len(speedColumns)
+	}
+	return 1
+}
 
-		for j := 1; j < tableColumns; j++ {
-			inputLen := sizeInBytesFunc(inputData[j-1])
-			outputLen := compressors[i].compress(inputData[j-1])
+// result is the measurement of one codec on one input.
+type result struct {
+	codec    string
+	integers int
+	density  float64
+	measurement
+	err error
+}
 
-			switch {
-			case i < headerRows:
-				table[i][j] = outputSizeString(outputLen)
-			case showRatio:
-				table[i][j] = ratioString(inputLen, outputLen)
-			default:
-				table[i][j] = outputSizeString(outputLen)
+// measureAll measures every codec on every input, returning the results
+// indexed by codec and then by input. densities holds the density of every
+// input, or is nil if they were not drawn at a given density.
+func measureAll(codecList []simple.Codec, inputData [][]uint32, densities []float64, opts tableOptions) [][]result {
+	results := make([][]result, len(codecList))
+	for i, codec := range codecList {
+		results[i] = make([]result, len(inputData))
+		for j, data := range inputData {
+			var density float64
+			if densities != nil {
+				density = densities[j]
 			}
+			m, err := measure(codec, data, opts)
+			results[i][j] = result{codec.Name(), len(data), density, m, err}
 		}
 	}
+	return results
+}
 
-	return table
+func failures(results [][]result) []error {
+	var errs []error
+	for _, row := range results {
+		for _, r := range row {
+			if r.err != nil {
+				errs = append(errs, fmt.Errorf("%s, %s integers: %w", r.codec, inputString(r.integers, r.density), r.err))
+			}
+		}
+	}
+	return errs
 }
 
-func printTable(table [][]string) {
-	rows := len(table)
-	w := tabwriter.NewWriter(os.Stdout, 0, 0, 3, ' ', tabwriter.AlignRight)
+// makeTable lays results out with one row per codec, marking failures as
+// FAIL. With speed enabled, every input gets the speed columns right after
+// its size or ratio column.
+func makeTable(results [][]result, opts tableOptions) [][]string {
+	perInput := columnsPerInput(opts)
+	table := make([][]string, 1+len(results))
+
+	table[0] = []string{"integers"}
+	for _, r := range results[0] {
+		table[0] = append(table[0], inputString(r.integers, r.density))
+		if opts.speed {
+			table[0] = append(table[0], speedColumns...)
+		}
+	}
+
+	for i, row := range results {
+		cells := make([]string, 0, 1+perInput*len(row))
+		cells = append(cells, row[0].codec)
 
-	for i := 0; i < rows; i++ {
-		var line strings.Builder
-		columns := len(table[i])
+		for _, r := range row {
+			inputLen := 4 * r.integers
 
-		for j := 0; j < columns; j++ {
-			line.WriteString(table[i][j])
-			line.WriteString("\t")
+			switch {
+			case r.err != nil:
+				cells = append(cells, "FAIL")
+				for k := 1; k < perInput; k++ {
+					cells = append(cells, "-")
+				}
+				continue
+			case opts.ratio:
+				cells = append(cells, ratioString(inputLen, r.outputLen))
+			default:
+				cells = append(cells, outputSizeString(r.outputLen))
+			}
+
+			if opts.speed {
+				cells = append(cells,
+					throughputString(inputLen, r.encodeTime),
+					nsPerIntString(r.integers, r.encodeTime),
+					throughputString(inputLen, r.decodeTime),
+					nsPerIntString(r.integers, r.decodeTime),
+				)
+			}
 		}
 
-		fmt.Fprintln(w, line.String())
+		table[1+i] = cells
 	}
 
-	w.Flush()
+	return table
+}
+
+func isFlagSet(name string) bool {
+	set := false
+	flag.Visit(func(f *flag.Flag) {
+		if f.Name == name {
+			set = true
+		}
+	})
+	return set
 }
 
 func usage() {
 	fmt.Println(`
 usage: compare-compression-ratio [-help] [-sizes=LIST] [-cardinality-header-size=SIZE] [-ratio]
+                                 [-offset=N] [-range=N] [-verify] [-speed] [-iterations=N]
+                                 [-format=table|csv|json|markdown] [-out=FILE]
+                                 [-input=FILES] [-input-format=text|binary|lists]
+                                 [-distribution=NAME] [-density=LIST] [-seed=N]
 
 options:`)
 	flag.PrintDefaults()
@@ -187,6 +312,18 @@ func main() {
 	sizesPtr := flag.String("sizes", "", "comma-separated sizes, e.g.: 10,100,1000")
 	cardHeaderSize := flag.Int("cardinality-header-size", 32, "cardinality header size")
 	ratioPtr := flag.Bool("ratio", false, "show compression ratio rather than output size")
+	offsetPtr := flag.Uint64("offset", 0, "smallest generated value, used along with -range")
+	rangePtr := flag.Uint64("range", 0, "generate values in [offset, offset+range) rather than in [0, 2^31)")
+	verifyPtr := flag.Bool("verify", false, "decode every output and check it matches the input")
+	speedPtr := flag.Bool("speed", false, "show encoding and decoding speed next to every size")
+	iterationsPtr := flag.Int("iterations", 10, "number of times every input is encoded and decoded to measure speed")
+	formatPtr := flag.String("format", formatTable, "output format: table, csv, json or markdown")
+	outPtr := flag.String("out", "", "write the output to FILE rather than to the standard output")
+	inputPtr := flag.String("input", "", "comma-separated files to read the lists from, rather than generating them; lists are sorted before use")
+	inputFormatPtr := flag.String("input-format", inputText, "format of the -input files: text, binary or lists")
+	seedPtr := flag.Int64("seed", 0, "seed of the generated lists (default: based on the current time)")
+	distributionPtr := flag.String("distribution", distributionUniform, "distribution of the generated values: "+strings.Join(distributions, ", "))
+	densityPtr := flag.String("density", "", "comma-separated densities in (0, 1], e.g.: 0.001,0.1,1; generates every size as a set filling that fraction of its range")
 
 	flag.Parse()
 
@@ -199,24 +336,144 @@ func main() {
 		log.Fatalln("invalid -cardinality-header-size value, must be between 1 and 32, both inclusive")
 	}
 
+	var inputPaths []string
+	if *inputPtr != "" {
+		inputPaths = strings.Split(*inputPtr, ",")
+	}
+
+	if !isInputFormatValid(*inputFormatPtr) {
+		log.Fatalln("invalid -input-format value, must be one of text, binary or lists")
+	}
+
 	sizes := parseSizes(*sizesPtr)
-	if len(sizes) == 0 {
+	if len(sizes) == 0 && len(inputPaths) == 0 {
 		log.Fatalln("missing or empty -sizes option")
 	}
 
-	randomSlices := makeRandomSlices(sizes)
-	compressors := []compressor{
-		{name: "integers", compress: sizeFunc},
-		{name: "bytes", compress: sizeInBytesFunc},
-		{name: "simple", compress: simpleCompress(*cardHeaderSize)},
-		{name: "zlib", compress: zlibCompress},
-		{name: "bp32", compress: bp32Compress},
-		{name: "delta bp32", compress: deltaBp32Compress},
-		{name: "fastpfor", compress: fastpforCompress},
-		{name: "delta fastpfor", compress: deltaFastpforCompress},
+	if !isRangeValid(*offsetPtr, *rangePtr) {
+		log.Fatalln("invalid -offset and -range values, offset+range must not exceed 2^32")
+	}
+
+	if *offsetPtr != 0 && *rangePtr == 0 {
+		log.Fatalln("-offset can only be used along with -range")
+	}
+
+	if !isDistributionValid(*distributionPtr) {
+		log.Fatalln("invalid -distribution value, must be one of " + strings.Join(distributions, ", "))
+	}
+
+	if *distributionPtr != distributionUniform && (*offsetPtr != 0 || *rangePtr != 0) {
+		log.Fatalln("-offset and -range can only be used with the uniform distribution")
+	}
+
+	var densities []float64
+	if *densityPtr != "" {
+		if densities = parseDensities(*densityPtr); len(densities) == 0 {
+			log.Fatalln("invalid -density value, must be a list of numbers in (0, 1]")
+		}
+		if len(inputPaths) > 0 || *distributionPtr != distributionUniform || *offsetPtr != 0 || *rangePtr != 0 {
+			log.Fatalln("-density cannot be used along with -input, -distribution, -offset or -range")
+		}
+		if !isDensityValid(sizes, densities) {
+			log.Fatalln("invalid -density value, size/density must not exceed 2^32")
+		}
+	}
+
+	if *iterationsPtr < 1 {
+		log.Fatalln("invalid -iterations value, must be at least 1")
+	}
+
+	if !isFormatValid(*formatPtr) {
+		log.Fatalln("invalid -format value, must be one of table, csv, json or markdown")
+	}
+
+	// the seed only matters, and is only reported, for generated lists
+	var seed *int64
+	var inputData [][]uint32
+	var inputDensities []float64
+	if len(inputPaths) > 0 {
+		var err error
+		if inputData, err = loadInputs(inputPaths, *inputFormatPtr); err != nil {
+			log.Fatalln(err)
+		}
+		if len(inputData) == 0 {
+			log.Fatalln("no lists in -input files")
+		}
+		sizes = make([]int, len(inputData))
+		for i, list := range inputData {
+			sizes[i] = len(list)
+		}
+	} else {
+		if !isFlagSet("seed") {
+			*seedPtr = time.Now().UnixNano()
+		}
+		seed = seedPtr
+		r := rand.New(rand.NewSource(*seedPtr))
+		if densities != nil {
+			inputData, inputDensities = makeDensitySlices(r, sizes, densities)
+		} else {
+			inputData = makeRandomSlices(r, *distributionPtr, sizes, *offsetPtr, *rangePtr)
+		}
+	}
+
+	registry := codecs.NewRegistry(*cardHeaderSize)
+
+	opts := tableOptions{
+		ratio:      *ratioPtr,
+		verify:     *verifyPtr,
+		speed:      *speedPtr,
+		iterations: *iterationsPtr,
 	}
+	results := measureAll(registry.Codecs(), inputData, inputDensities, opts)
 
-	table := makeTable(compressors, randomSlices, 1, *ratioPtr)
+	out := os.Stdout
+	if *outPtr != "" {
+		f, err := os.Create(*outPtr)
+		if err != nil {
+			log.Fatalln(err)
+		}
+		out = f
+	}
 
-	printTable(table)
+	var err error
+	switch *formatPtr {
+	case formatJSON:
+		err = writeJSON(out, makeReport(results, parameters{
+			CardinalityHeaderSize: *cardHeaderSize,
+			Sizes:                 sizes,
+			Distribution:          distribution(inputPaths, *distributionPtr),
+			Densities:             densities,
+			Seed:                  seed,
+			Input:                 inputPaths,
+			InputFormat:           inputFormat(inputPaths, *inputFormatPtr),
+			Offset:                *offsetPtr,
+			Range:                 *rangePtr,
+			Verify:                *verifyPtr,
+			Speed:                 *speedPtr,
+			Iterations:            *iterationsPtr,
+		}))
+	case formatCSV:
+		err = writeCSV(out, seed, makeTable(results, opts))
+	case formatMarkdown:
+		err = writeMarkdown(out, headerLines(seed), makeTable(results, opts))
+	default:
+		err = writeTable(out, headerLines(seed), makeTable(results, opts))
+	}
+	if err != nil {
+		log.Fatalln(err)
+	}
+
+	if out != os.Stdout {
+		if err := out.Close(); err != nil {
+			log.Fatalln(err)
+		}
+	}
+
+	errs := failures(results)
+	for _, err := range errs {
+		log.Println(err)
+	}
+	if len(errs) > 0 {
+		os.Exit(1)
+	}
 }
@@ -0,0 +1,133 @@
+package main
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/vteromero/playground/simple-integer-list-compression"
+	"github.com/vteromero/playground/simple-integer-list-compression/codecs"
+	"github.com/stretchr/testify/assert"
+)
+
+// offByOne decodes every value one above what was encoded.
+type offByOne struct {
+	simple.Codec
+}
+
+func (c offByOne) Decode(input []byte, output []uint32) ([]uint32, error) {
+	output, err := c.Codec.Decode(input, output)
+	for i := range output {
+		output[i]++
+	}
+	return output, err
+}
+
+func TestIsRangeValid(t *testing.T) {
+	params := []struct {
+		offset   uint64
+		size     uint64
+		expected bool
+	}{
+		{0, 0, true},
+		{0, 1 << 32, true},
+		{3000000000, 1294967296, true},
+		{3000000000, 1294967297, false},
+		{1 << 32, 0, true},
+		{1<<32 + 1, 0, false},
+		{18446744073709551615, 2, false},
+		{2, 18446744073709551615, false},
+	}
+
+	for _, testCase := range params {
+		assert.Equal(t, testCase.expected, isRangeValid(testCase.offset, testCase.size), "%d %d", testCase.offset, testCase.size)
+	}
+}
+
+func TestVerify(t *testing.T) {
+	data := []uint32{5, 111, 8888}
+
+	params := []struct {
+		codec       simple.Codec
+		expectedErr error
+	}{
+		{codecs.Raw(), nil},
+		{offByOne{codecs.Raw()}, errMismatch},
+	}
+
+	for _, testCase := range params {
+		encoded, err := encode(testCase.codec, data)
+		assert.Nil(t, err)
+		assert.Equal(t, testCase.expectedErr, verify(testCase.codec, data, encoded))
+	}
+
+	// the input is left as it was
+	assert.Equal(t, []uint32{5, 111, 8888}, data)
+}
+
+func TestMakeTable(t *testing.T) {
+	errFailed := errors.New("failed")
+	m := measurement{outputLen: 2000, encodeTime: time.Millisecond, decodeTime: 2 * time.Millisecond}
+	results := [][]result{
+		{
+			{"simple", 1000, 0, m, nil},
+			{"simple", 2000, 0, m, errFailed},
+		},
+		{
+			{"zlib", 1000, 0, m, errFailed},
+			{"zlib", 2000, 0, m, nil},
+		},
+	}
+
+	// an empty input, and an input encoded and decoded faster than the
+	// clock can tell
+	edgeResults := [][]result{
+		{
+			{"simple", 0, 0, measurement{outputLen: 4}, nil},
+			{"simple", 1000, 0.5, measurement{outputLen: 500}, nil},
+		},
+	}
+
+	params := []struct {
+		results  [][]result
+		opts     tableOptions
+		expected [][]string
+	}{
+		{results, tableOptions{}, [][]string{
+			{"integers", "1000", "2000"},
+			{"simple", "2000", "FAIL"},
+			{"zlib", "FAIL", "2000"},
+		}},
+		{results, tableOptions{ratio: true}, [][]string{
+			{"integers", "1000", "2000"},
+			{"simple", "2.00", "FAIL"},
+			{"zlib", "FAIL", "4.00"},
+		}},
+		{results, tableOptions{speed: true}, [][]string{
+			{"integers", "1000", "enc MB/s", "enc ns/int", "dec MB/s", "dec ns/int", "2000", "enc MB/s", "enc ns/int", "dec MB/s", "dec ns/int"},
+			{"simple", "2000", "4.0", "1000.00", "2.0", "2000.00", "FAIL", "-", "-", "-", "-"},
+			{"zlib", "FAIL", "-", "-", "-", "-", "2000", "8.0", "500.00", "4.0", "1000.00"},
+		}},
+		{results, tableOptions{ratio: true, speed: true}, [][]string{
+			{"integers", "1000", "enc MB/s", "enc ns/int", "dec MB/s", "dec ns/int", "2000", "enc MB/s", "enc ns/int", "dec MB/s", "dec ns/int"},
+			{"simple", "2.00", "4.0", "1000.00", "2.0", "2000.00", "FAIL", "-", "-", "-", "-"},
+			{"zlib", "FAIL", "-", "-", "-", "-", "4.00", "8.0", "500.00", "4.0", "1000.00"},
+		}},
+		{edgeResults, tableOptions{}, [][]string{
+			{"integers", "0", "1000@0.5"},
+			{"simple", "4", "500"},
+		}},
+		{edgeResults, tableOptions{ratio: true, speed: true}, [][]string{
+			{"integers", "0", "enc MB/s", "enc ns/int", "dec MB/s", "dec ns/int", "1000@0.5", "enc MB/s", "enc ns/int", "dec MB/s", "dec ns/int"},
+			{"simple", "0.00", "-", "-", "-", "-", "8.00", "inf", "0.00", "inf", "0.00"},
+		}},
+	}
+
+	for _, testCase := range params {
+		table := makeTable(testCase.results, testCase.opts)
+		assert.Equal(t, testCase.expected, table)
+		for _, row := range table {
+			assert.Equal(t, 1+columnsPerInput(testCase.opts)*len(testCase.results[0]), len(row))
+		}
+	}
+}
@@ -0,0 +1,197 @@
+package main
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+)
+
+const (
+	formatTable    = "table"
+	formatCSV      = "csv"
+	formatJSON     = "json"
+	formatMarkdown = "markdown"
+)
+
+func isFormatValid(format string) bool {
+	switch format {
+	case formatTable, formatCSV, formatJSON, formatMarkdown:
+		return true
+	}
+	return false
+}
+
+// parameters records the options a report was produced with, so that
+// reports stored over time can be told apart.
+type parameters struct {
+	CardinalityHeaderSize int       `json:"cardinality_header_size"`
+	Sizes                 []int     `json:"sizes"`
+	Distribution          string    `json:"distribution,omitempty"`
+	Densities             []float64 `json:"densities,omitempty"`
+	Seed                  *int64    `json:"seed,omitempty"`
+	Input                 []string  `json:"input,omitempty"`
+	InputFormat           string    `json:"input_format,omitempty"`
+	Offset                uint64    `json:"offset"`
+	Range                 uint64    `json:"range"`
+	Verify                bool      `json:"verify"`
+	Speed                 bool      `json:"speed"`
+	Iterations            int       `json:"iterations,omitempty"`
+}
+
+type reportResult struct {
+	Codec          string   `json:"codec"`
+	InputSize      int      `json:"input_size"`
+	Density        float64  `json:"density,omitempty"`
+	InputBytes     int      `json:"input_bytes"`
+	Bytes          int      `json:"bytes"`
+	Ratio          *float64 `json:"ratio,omitempty"`
+	EncodeMBPerSec *float64 `json:"encode_mb_per_sec,omitempty"`
+	EncodeNsPerInt *float64 `json:"encode_ns_per_int,omitempty"`
+	DecodeMBPerSec *float64 `json:"decode_mb_per_sec,omitempty"`
+	DecodeNsPerInt *float64 `json:"decode_ns_per_int,omitempty"`
+	Error          string   `json:"error,omitempty"`
+}
+
+type report struct {
+	Parameters parameters     `json:"parameters"`
+	Results    []reportResult `json:"results"`
+}
+
+// ratio divides a by b, returning nil when the result is not a finite
+// number, as JSON cannot represent those.
+func ratio(a, b float64) *float64 {
+	if b <= 0 {
+		return nil
+	}
+	r := a / b
+	return &r
+}
+
+func makeReport(results [][]result, params parameters) report {
+	rep := report{Parameters: params, Results: []reportResult{}}
+	if !params.Speed {
+		rep.Parameters.Iterations = 0
+	}
+
+	for _, row := range results {
+		for _, r := range row {
+			rr := reportResult{
+				Codec:      r.codec,
+				InputSize:  r.integers,
+				Density:    r.density,
+				InputBytes: 4 * r.integers,
+			}
+
+			if r.err != nil {
+				rr.Error = r.err.Error()
+				rep.Results = append(rep.Results, rr)
+				continue
+			}
+
+			rr.Bytes = r.outputLen
+			rr.Ratio = ratio(float64(rr.InputBytes), float64(r.outputLen))
+			if params.Speed && r.integers > 0 {
+				rr.EncodeMBPerSec = ratio(float64(rr.InputBytes)/1e6, r.encodeTime.Seconds())
+				rr.EncodeNsPerInt = ratio(float64(r.encodeTime.Nanoseconds()), float64(r.integers))
+				rr.DecodeMBPerSec = ratio(float64(rr.InputBytes)/1e6, r.decodeTime.Seconds())
+				rr.DecodeNsPerInt = ratio(float64(r.decodeTime.Nanoseconds()), float64(r.integers))
+			}
+			rep.Results = append(rep.Results, rr)
+		}
+	}
+
+	return rep
+}
+
+func writeJSON(w io.Writer, rep report) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(rep)
+}
+
+// headerLines returns the lines printed before the table in the text
+// formats, needed to reproduce the results.
+func headerLines(seed *int64) []string {
+	if seed == nil {
+		return nil
+	}
+	return []string{fmt.Sprintf("seed: %d", *seed)}
+}
+
+func writeTable(w io.Writer, header []string, table [][]string) error {
+	for _, line := range header {
+		if _, err := fmt.Fprintln(w, line); err != nil {
+			return err
+		}
+	}
+
+	tw := tabwriter.NewWriter(w, 0, 0, 3, ' ', tabwriter.AlignRight)
+
+	for _, row := range table {
+		var line strings.Builder
+
+		for _, cell := range row {
+			line.WriteString(cell)
+			line.WriteString("\t")
+		}
+
+		fmt.Fprintln(tw, line.String())
+	}
+
+	return tw.Flush()
+}
+
+// writeCSV writes table as plain CSV. The seed, if any, goes in a last
+// column of every row, so that any CSV reader gets it along with the
+// results.
+func writeCSV(w io.Writer, seed *int64, table [][]string) error {
+	if seed != nil {
+		withSeed := make([][]string, len(table))
+		for i, row := range table {
+			cell := strconv.FormatInt(*seed, 10)
+			if i == 0 {
+				cell = "seed"
+			}
+			withSeed[i] = append(row[:len(row):len(row)], cell)
+		}
+		table = withSeed
+	}
+
+	cw := csv.NewWriter(w)
+	if err := cw.WriteAll(table); err != nil {
+		return err
+	}
+	return cw.Error()
+}
+
+// writeMarkdown writes table as a Markdown table, taking its first row as
+// the header.
+func writeMarkdown(w io.Writer, header []string, table [][]string) error {
+	for _, line := range header {
+		if _, err := fmt.Fprintf(w, "%s\n\n", line); err != nil {
+			return err
+		}
+	}
+
+	for i, row := range table {
+		if _, err := fmt.Fprintf(w, "| %s |\n", strings.Join(row, " | ")); err != nil {
+			return err
+		}
+
+		if i == 0 {
+			align := make([]string, len(row))
+			align[0] = "---"
+			for j := 1; j < len(row); j++ {
+				align[j] = "---:"
+			}
+			if _, err := fmt.Fprintf(w, "| %s |\n", strings.Join(align, " | ")); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,159 @@
+package main
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func float(f float64) *float64 {
+	return &f
+}
+
+func TestWriteCSV(t *testing.T) {
+	table := [][]string{
+		{"codec", "1000"},
+		{"simple", "1.50"},
+		{"zlib", "1.20"},
+	}
+	seed := int64(42)
+
+	params := []struct {
+		seed     *int64
+		expected [][]string
+	}{
+		{nil, table},
+		{&seed, [][]string{
+			{"codec", "1000", "seed"},
+			{"simple", "1.50", "42"},
+			{"zlib", "1.20", "42"},
+		}},
+	}
+
+	for _, testCase := range params {
+		var buf bytes.Buffer
+		assert.Nil(t, writeCSV(&buf, testCase.seed, table))
+
+		// a reader with default settings gets the table back
+		records, err := csv.NewReader(&buf).ReadAll()
+		assert.Nil(t, err)
+		assert.Equal(t, testCase.expected, records)
+	}
+
+	assert.Equal(t, []string{"codec", "1000"}, table[0])
+}
+
+func TestWriteJSON(t *testing.T) {
+	m := measurement{outputLen: 2000, encodeTime: time.Millisecond, decodeTime: 2 * time.Millisecond}
+	results := [][]result{
+		{
+			{"simple", 0, 0, measurement{outputLen: 4}, nil},
+			{"simple", 1000, 0.5, m, nil},
+		},
+		{
+			{"zlib", 0, 0, measurement{outputLen: 8}, nil},
+			{"zlib", 1000, 0.5, m, errors.New("failed")},
+		},
+	}
+	seed := int64(42)
+
+	params := []struct {
+		speed    bool
+		expected report
+	}{
+		{false, report{
+			Parameters: parameters{CardinalityHeaderSize: 32, Sizes: []int{0, 1000}, Densities: []float64{0.5}, Seed: &seed},
+			Results: []reportResult{
+				{Codec: "simple", InputBytes: 0, Bytes: 4, Ratio: float(0)},
+				{Codec: "simple", InputSize: 1000, Density: 0.5, InputBytes: 4000, Bytes: 2000, Ratio: float(2)},
+				{Codec: "zlib", InputBytes: 0, Bytes: 8, Ratio: float(0)},
+				{Codec: "zlib", InputSize: 1000, Density: 0.5, InputBytes: 4000, Error: "failed"},
+			},
+		}},
+		{true, report{
+			Parameters: parameters{CardinalityHeaderSize: 32, Sizes: []int{0, 1000}, Densities: []float64{0.5}, Seed: &seed, Speed: true, Iterations: 10},
+			Results: []reportResult{
+				{Codec: "simple", InputBytes: 0, Bytes: 4, Ratio: float(0)},
+				{
+					Codec: "simple", InputSize: 1000, Density: 0.5, InputBytes: 4000, Bytes: 2000, Ratio: float(2),
+					EncodeMBPerSec: float(4), EncodeNsPerInt: float(1000), DecodeMBPerSec: float(2), DecodeNsPerInt: float(2000),
+				},
+				{Codec: "zlib", InputBytes: 0, Bytes: 8, Ratio: float(0)},
+				{Codec: "zlib", InputSize: 1000, Density: 0.5, InputBytes: 4000, Error: "failed"},
+			},
+		}},
+	}
+
+	for _, testCase := range params {
+		// iterations are only reported along with speed
+		var buf bytes.Buffer
+		assert.Nil(t, writeJSON(&buf, makeReport(results, parameters{
+			CardinalityHeaderSize: 32,
+			Sizes:                 []int{0, 1000},
+			Densities:             []float64{0.5},
+			Seed:                  &seed,
+			Speed:                 testCase.speed,
+			Iterations:            10,
+		})))
+
+		var rep report
+		assert.Nil(t, json.Unmarshal(buf.Bytes(), &rep))
+		assert.Equal(t, testCase.expected, rep)
+
+		// missing ratios and speeds are left out rather than written as null
+		var raw struct {
+			Results []map[string]any `json:"results"`
+		}
+		assert.Nil(t, json.Unmarshal(buf.Bytes(), &raw))
+		for i, r := range raw.Results {
+			fields := map[string]*float64{
+				"ratio":             rep.Results[i].Ratio,
+				"encode_mb_per_sec": rep.Results[i].EncodeMBPerSec,
+				"encode_ns_per_int": rep.Results[i].EncodeNsPerInt,
+				"decode_mb_per_sec": rep.Results[i].DecodeMBPerSec,
+				"decode_ns_per_int": rep.Results[i].DecodeNsPerInt,
+			}
+			for key, v := range fields {
+				_, ok := r[key]
+				assert.Equal(t, v != nil, ok, key)
+			}
+		}
+	}
+}
+
+func TestWriteMarkdown(t *testing.T) {
+	table := [][]string{
+		{"integers", "1000", "2000"},
+		{"simple", "2.00", "FAIL"},
+		{"zlib", "1.20", "1.30"},
+	}
+
+	params := []struct {
+		header   []string
+		expected string
+	}{
+		{nil, "" +
+			"| integers | 1000 | 2000 |\n" +
+			"| --- | ---: | ---: |\n" +
+			"| simple | 2.00 | FAIL |\n" +
+			"| zlib | 1.20 | 1.30 |\n"},
+		{headerLines(new(int64)), "" +
+			"seed: 0\n" +
+			"\n" +
+			"| integers | 1000 | 2000 |\n" +
+			"| --- | ---: | ---: |\n" +
+			"| simple | 2.00 | FAIL |\n" +
+			"| zlib | 1.20 | 1.30 |\n"},
+	}
+
+	for _, testCase := range params {
+		var buf bytes.Buffer
+		assert.Nil(t, writeMarkdown(&buf, testCase.header, table))
+		assert.Equal(t, testCase.expected, buf.String())
+	}
+}
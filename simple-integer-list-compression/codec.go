@@ -0,0 +1,75 @@
+package simple
+
+// Codec is the common interface of the list codecs compared by the
+// benchmarks and by compare-compression-ratio.
+type Codec interface {
+	Name() string
+
+	// MaxEncodedLen returns the size of the output buffer that Encode needs
+	// for n values.
+	MaxEncodedLen(n int) int
+
+	// Encode writes values into output and returns the number of bytes
+	// written.
+	Encode(values []uint32, output []byte) (int, error)
+
+	// Decode decodes input into output, which should have room for all the
+	// values, and returns them.
+	Decode(input []byte, output []uint32) ([]uint32, error)
+}
+
+type codec struct {
+	name         string
+	compressor   *Compressor
+	decompressor *Decompressor
+}
+
+// NewCodec returns a Codec that encodes with c and decodes with a
+// decompressor matching its options, MaxCardinality included.
+func NewCodec(name string, c *Compressor) Codec {
+	return &codec{
+		name:         name,
+		compressor:   c,
+		decompressor: c.decompressor(),
+	}
+}
+
+func (c *GenericCompressor[T]) decompressor() *GenericDecompressor[T] {
+	if c.Framed {
+		return &GenericDecompressor[T]{Framed: true, MaxCardinality: c.MaxCardinality, signed: c.signed}
+	}
+	return &GenericDecompressor[T]{
+		ListOrder:             c.ListOrder,
+		CardinalityHeaderSize: c.CardinalityHeaderSize,
+		Blocked:               c.BlockSize > 0,
+		Delta:                 c.Delta,
+		Forward:               c.Forward,
+		Adaptive:              c.Adaptive,
+		Hybrid:                c.Hybrid,
+		Runs:                  c.Runs,
+		FrameOfReference:      c.FrameOfReference,
+		Checksum:              c.Checksum,
+		MaxCardinality:        c.MaxCardinality,
+		signed:                c.signed,
+	}
+}
+
+func (c *codec) Name() string {
+	return c.name
+}
+
+func (c *codec) MaxEncodedLen(n int) int {
+	return c.compressor.MaxCompressedLen(n)
+}
+
+func (c *codec) Encode(values []uint32, output []byte) (int, error) {
+	n, err := c.compressor.Compress(values, output)
+	if err != nil {
+		return 0, err
+	}
+	return sizeInBytes(n), nil
+}
+
+func (c *codec) Decode(input []byte, output []uint32) ([]uint32, error) {
+	return c.decompressor.DecompressInto(input, output)
+}
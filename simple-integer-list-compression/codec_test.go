@@ -0,0 +1,101 @@
+package simple
+
+import (
+	"testing"
+
+	"github.com/vteromero/playground/simple-integer-list-compression/slice"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCodec_EncodeAndDecode(t *testing.T) {
+	blocked := NewCompressor(OrderAscending, 32)
+	blocked.BlockSize = DefaultBlockSize
+	blocked.Checksum = true
+
+	framed := NewCompressor(OrderDescending, 32)
+	framed.Framed = true
+	framed.Delta = true
+
+	forward := NewCompressor(OrderAscending, 32)
+	forward.Forward = true
+
+	params := []struct {
+		compressor *Compressor
+		inputSize  int
+	}{
+		{NewCompressor(OrderAscending, 32), 0},
+		{NewCompressor(OrderAscending, 32), 1000},
+		{NewCompressor(OrderDescending, 16), 1000},
+		{blocked, 1000},
+		{framed, 1000},
+		{forward, 1000},
+	}
+
+	for _, testCase := range params {
+		var input []uint32
+		if testCase.compressor.ListOrder == OrderAscending {
+			input = slice.SortAscUint32Slice(slice.RandomUint32Slice(testCase.inputSize))
+		} else {
+			input = slice.SortDescUint32Slice(slice.RandomUint32Slice(testCase.inputSize))
+		}
+
+		codec := NewCodec("simple", testCase.compressor)
+		assert.Equal(t, "simple", codec.Name())
+
+		encoded := make([]byte, codec.MaxEncodedLen(len(input)))
+		n, err := codec.Encode(input, encoded)
+		assert.Nil(t, err)
+		assert.LessOrEqual(t, n, len(encoded))
+
+		output, err := codec.Decode(encoded[:n], make([]uint32, len(input)))
+		assert.Nil(t, err)
+		assert.Equal(t, input, output)
+	}
+}
+
+func TestCodec_DecodeRuns(t *testing.T) {
+	input := rangeUint32Slice(1000, 1000)
+
+	for _, framed := range []bool{false, true} {
+		c := NewCompressor(OrderAscending, 32)
+		c.Runs = true
+		c.Framed = framed
+		codec := NewCodec("simple runs", c)
+
+		encoded := make([]byte, codec.MaxEncodedLen(len(input)))
+		n, err := codec.Encode(input, encoded)
+		assert.Nil(t, err)
+
+		output, err := codec.Decode(encoded[:n], make([]uint32, len(input)))
+		assert.Nil(t, err)
+		assert.Equal(t, input, output)
+
+		// decoding allocates when output has no room for the values
+		output, err = codec.Decode(encoded[:n], make([]uint32, 0, 10))
+		assert.Nil(t, err)
+		assert.Equal(t, input, output)
+	}
+}
+
+func TestCodec_MaxCardinality(t *testing.T) {
+	input := rangeUint32Slice(1000, 100000)
+
+	// the codec decodes with the bound it encodes with
+	c := NewCompressor(OrderAscending, 32)
+	c.Runs = true
+	codec := NewCodec("simple runs", c)
+	_, err := codec.Encode(input, make([]byte, codec.MaxEncodedLen(len(input))))
+	assert.Equal(t, ErrCardinalityTooLarge, err)
+
+	c = NewCompressor(OrderAscending, 32)
+	c.Runs = true
+	c.MaxCardinality = len(input)
+	codec = NewCodec("simple runs", c)
+	encoded := make([]byte, codec.MaxEncodedLen(len(input)))
+	n, err := codec.Encode(input, encoded)
+	assert.Nil(t, err)
+
+	output, err := codec.Decode(encoded[:n], nil)
+	assert.Nil(t, err)
+	assert.Equal(t, input, output)
+}
@@ -0,0 +1,299 @@
+// Package codecs adapts the codecs that the simple codec is compared against
+// to the simple.Codec interface, and keeps a registry of them.
+package codecs
+
+import (
+	"bytes"
+	"compress/zlib"
+	"encoding/binary"
+	"io"
+
+	"github.com/vteromero/playground/simple-integer-list-compression"
+	"github.com/dataence/encoding"
+	"github.com/dataence/encoding/bp32"
+	"github.com/dataence/encoding/composition"
+	"github.com/dataence/encoding/cursor"
+	deltabp32 "github.com/dataence/encoding/delta/bp32"
+	deltafastpfor "github.com/dataence/encoding/delta/fastpfor"
+	deltavb "github.com/dataence/encoding/delta/variablebyte"
+	"github.com/dataence/encoding/fastpfor"
+	"github.com/dataence/encoding/variablebyte"
+)
+
+func putUint32s(output []byte, values []uint32) {
+	for i, v := range values {
+		binary.LittleEndian.PutUint32(output[4*i:], v)
+	}
+}
+
+func uint32s(input []byte, output []uint32) []uint32 {
+	n := len(input) / 4
+	if cap(output) < n {
+		output = make([]uint32, n)
+	}
+	output = output[:n]
+	for i := range output {
+		output[i] = binary.LittleEndian.Uint32(input[4*i:])
+	}
+	return output
+}
+
+// raw stores the values as they are, 4 bytes each in little-endian order.
+type raw struct{}
+
+func Raw() simple.Codec {
+	return raw{}
+}
+
+func (raw) Name() string {
+	return "bytes"
+}
+
+func (raw) MaxEncodedLen(n int) int {
+	return 4 * n
+}
+
+func (raw) Encode(values []uint32, output []byte) (int, error) {
+	if len(output) < 4*len(values) {
+		return 0, simple.ErrOutputTooShort
+	}
+	putUint32s(output, values)
+	return 4 * len(values), nil
+}
+
+func (raw) Decode(input []byte, output []uint32) ([]uint32, error) {
+	if len(input)%4 != 0 {
+		return nil, simple.ErrTruncatedInput
+	}
+	return uint32s(input, output), nil
+}
+
+type zlibCodec struct {
+	level int
+}
+
+func Zlib(level int) simple.Codec {
+	return &zlibCodec{level: level}
+}
+
+func (z *zlibCodec) Name() string {
+	return "zlib"
+}
+
+// MaxEncodedLen follows the deflate bound of zlib's compressBound.
+func (z *zlibCodec) MaxEncodedLen(n int) int {
+	size := 4 * n
+	return size + size>>12 + size>>14 + size>>25 + 13 + 6
+}
+
+func (z *zlibCodec) Encode(values []uint32, output []byte) (int, error) {
+	var buf bytes.Buffer
+	writer, err := zlib.NewWriterLevel(&buf, z.level)
+	if err != nil {
+		return 0, err
+	}
+
+	in := make([]byte, 4*len(values))
+	putUint32s(in, values)
+	if _, err := writer.Write(in); err != nil {
+		return 0, err
+	}
+	if err := writer.Close(); err != nil {
+		return 0, err
+	}
+
+	if buf.Len() > len(output) {
+		return 0, simple.ErrOutputTooShort
+	}
+	return copy(output, buf.Bytes()), nil
+}
+
+func (z *zlibCodec) Decode(input []byte, output []uint32) ([]uint32, error) {
+	reader, err := zlib.NewReader(bytes.NewReader(input))
+	if err != nil {
+		return nil, err
+	}
+	defer reader.Close()
+
+	out, err := io.ReadAll(reader)
+	if err != nil {
+		return nil, err
+	}
+	if len(out)%4 != 0 {
+		return nil, simple.ErrTruncatedInput
+	}
+	return uint32s(out, output), nil
+}
+
+// integerCodec adapts a codec of github.com/dataence/encoding, which works
+// on int32 words, by reinterpreting values as int32 and writing the output
+// words in little-endian order. The int32 buffers are kept between calls,
+// so it is not safe for concurrent use.
+type integerCodec struct {
+	name  string
+	codec encoding.Integer
+	in    []int32
+	out   []int32
+}
+
+func growInt32s(s []int32, n int) []int32 {
+	if cap(s) < n {
+		return make([]int32, n)
+	}
+	return s[:n]
+}
+
+func Integer(name string, codec encoding.Integer) simple.Codec {
+	return &integerCodec{name: name, codec: codec}
+}
+
+func (c *integerCodec) Name() string {
+	return c.name
+}
+
+// Integer returns the wrapped codec, so that it can be run on int32 words
+// without the conversions that Encode and Decode make.
+func (c *integerCodec) Integer() encoding.Integer {
+	return c.codec
+}
+
+// MaxEncodedLen leaves room for twice the input plus the block headers, as
+// the compositions may fall back to variable byte for the tail.
+func (c *integerCodec) MaxEncodedLen(n int) int {
+	return 4 * (2*n + 1024)
+}
+
+func (c *integerCodec) Encode(values []uint32, output []byte) (int, error) {
+	// the wrapped codecs write past the end of a short output rather than
+	// failing
+	if len(output) < c.MaxEncodedLen(len(values)) {
+		return 0, simple.ErrOutputTooShort
+	}
+
+	c.in = growInt32s(c.in, len(values))
+	for i, v := range values {
+		c.in[i] = int32(v)
+	}
+
+	c.out = growInt32s(c.out, len(output)/4)
+	inpos, outpos := cursor.New(), cursor.New()
+	if err := c.codec.Compress(c.in, inpos, len(c.in), c.out, outpos); err != nil {
+		return 0, err
+	}
+
+	for i, v := range c.out[:outpos.Get()] {
+		binary.LittleEndian.PutUint32(output[4*i:], uint32(v))
+	}
+	return 4 * outpos.Get(), nil
+}
+
+// maxValuesPerWord bounds the values that a word of the compositions
+// decodes to: fastpfor, the densest of them, packs a block of 256 values of
+// width zero in two bytes.
+const maxValuesPerWord = 1024
+
+// maxDecodedLen bounds the number of values that in decodes to. The
+// compositions start with the number of values in their full blocks, and
+// their variable byte tail takes at least a byte per value. Inputs claiming
+// more values than their words can hold are reported as corrupt.
+func maxDecodedLen(in []int32) (int, error) {
+	if len(in) == 0 {
+		return 0, nil
+	}
+	n := int(in[0])
+	if n < 0 || n > maxValuesPerWord*len(in) {
+		return 0, simple.ErrCorruptInput
+	}
+	return n + 4*len(in), nil
+}
+
+func (c *integerCodec) Decode(input []byte, output []uint32) ([]uint32, error) {
+	if len(input)%4 != 0 {
+		return nil, simple.ErrTruncatedInput
+	}
+
+	c.in = growInt32s(c.in, len(input)/4)
+	for i := range c.in {
+		c.in[i] = int32(binary.LittleEndian.Uint32(input[4*i:]))
+	}
+
+	n, err := maxDecodedLen(c.in)
+	if err != nil {
+		return nil, err
+	}
+
+	c.out = growInt32s(c.out, n)
+	inpos, outpos := cursor.New(), cursor.New()
+	if err := c.codec.Uncompress(c.in, inpos, len(c.in), c.out, outpos); err != nil {
+		return nil, err
+	}
+
+	n = outpos.Get()
+	if cap(output) < n {
+		output = make([]uint32, n)
+	}
+	output = output[:n]
+	for i, v := range c.out[:n] {
+		output[i] = uint32(v)
+	}
+	return output, nil
+}
+
+func simpleCodec(name string, cardHeaderSize int, setup func(*simple.Compressor)) simple.Codec {
+	c := simple.NewCompressor(simple.OrderAscending, cardHeaderSize)
+	if setup != nil {
+		setup(c)
+	}
+	return simple.NewCodec(name, c)
+}
+
+type Registry struct {
+	codecs []simple.Codec
+}
+
+// NewRegistry returns a registry holding the codecs compared by default,
+// with the simple ones using the given cardinality header size. All of them
+// take ascending lists.
+func NewRegistry(cardHeaderSize int) *Registry {
+	r := &Registry{}
+	r.Register(Raw())
+	r.Register(simpleCodec("simple", cardHeaderSize, nil))
+	r.Register(simpleCodec("simple delta", cardHeaderSize, func(c *simple.Compressor) { c.Delta = true }))
+	r.Register(simpleCodec("simple for", cardHeaderSize, func(c *simple.Compressor) { c.FrameOfReference = true }))
+	r.Register(simpleCodec("simple forward", cardHeaderSize, func(c *simple.Compressor) { c.Forward = true }))
+	r.Register(simpleCodec("simple adaptive", cardHeaderSize, func(c *simple.Compressor) { c.Adaptive = true }))
+	r.Register(simpleCodec("simple hybrid", cardHeaderSize, func(c *simple.Compressor) { c.Hybrid = true }))
+	r.Register(simpleCodec("simple runs", cardHeaderSize, func(c *simple.Compressor) { c.Runs = true }))
+	r.Register(Zlib(zlib.BestCompression))
+	r.Register(Integer("bp32", composition.New(bp32.New(), variablebyte.New())))
+	r.Register(Integer("delta bp32", composition.New(deltabp32.New(), deltavb.New())))
+	r.Register(Integer("fastpfor", composition.New(fastpfor.New(), variablebyte.New())))
+	r.Register(Integer("delta fastpfor", composition.New(deltafastpfor.New(), deltavb.New())))
+	return r
+}
+
+// Register adds codec to the registry, replacing any codec with the same
+// name.
+func (r *Registry) Register(codec simple.Codec) {
+	for i, c := range r.codecs {
+		if c.Name() == codec.Name() {
+			r.codecs[i] = codec
+			return
+		}
+	}
+	r.codecs = append(r.codecs, codec)
+}
+
+// Codecs returns the registered codecs in registration order.
+func (r *Registry) Codecs() []simple.Codec {
+	return r.codecs
+}
+
+func (r *Registry) Lookup(name string) (simple.Codec, bool) {
+	for _, c := range r.codecs {
+		if c.Name() == name {
+			return c, true
+		}
+	}
+	return nil, false
+}
@@ -0,0 +1,101 @@
+package codecs
+
+import (
+	"compress/zlib"
+	"testing"
+
+	"github.com/vteromero/playground/simple-integer-list-compression"
+	"github.com/vteromero/playground/simple-integer-list-compression/slice"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRegistry_EncodeAndDecode(t *testing.T) {
+	r := NewRegistry(32)
+
+	for _, n := range []int{0, 1, 1000} {
+		input := slice.Int32ToUint32Slice(slice.SortAscInt32Slice(slice.RandomInt31Slice(n)))
+
+		for _, codec := range r.Codecs() {
+			encoded := make([]byte, codec.MaxEncodedLen(n))
+			m, err := codec.Encode(input, encoded)
+			assert.Nil(t, err, codec.Name())
+
+			output, err := codec.Decode(encoded[:m], make([]uint32, n))
+			assert.Nil(t, err, codec.Name())
+			assert.Equal(t, input, output, codec.Name())
+
+			// decoding allocates when output has no room for the values
+			output, err = codec.Decode(encoded[:m], nil)
+			assert.Nil(t, err, codec.Name())
+			assert.Equal(t, len(input), len(output), codec.Name())
+			if n > 0 {
+				assert.Equal(t, input, output, codec.Name())
+			}
+
+			output, err = codec.Decode(encoded[:m], make([]uint32, 0, 10))
+			assert.Nil(t, err, codec.Name())
+			assert.Equal(t, len(input), len(output), codec.Name())
+			if n > 0 {
+				assert.Equal(t, input, output, codec.Name())
+			}
+		}
+	}
+}
+
+func TestRegistry_RegisterAndLookup(t *testing.T) {
+	r := NewRegistry(32)
+	n := len(r.Codecs())
+
+	c, ok := r.Lookup("zlib")
+	assert.True(t, ok)
+	assert.Equal(t, "zlib", c.Name())
+
+	_, ok = r.Lookup("unknown")
+	assert.False(t, ok)
+
+	r.Register(Zlib(zlib.BestSpeed))
+	assert.Equal(t, n, len(r.Codecs()))
+
+	r.Register(simple.NewCodec("simple desc", simple.NewCompressor(simple.OrderDescending, 32)))
+	assert.Equal(t, n+1, len(r.Codecs()))
+	assert.Equal(t, "simple desc", r.Codecs()[n].Name())
+}
+
+func TestRaw_DecodeTruncated(t *testing.T) {
+	_, err := Raw().Decode([]byte{0x01, 0x02, 0x03}, nil)
+	assert.Equal(t, simple.ErrTruncatedInput, err)
+}
+
+func TestInteger_EncodeShortOutput(t *testing.T) {
+	input := slice.Int32ToUint32Slice(slice.SortAscInt32Slice(slice.RandomInt31Slice(1000)))
+
+	for _, name := range []string{"bp32", "delta bp32", "fastpfor", "delta fastpfor"} {
+		codec, ok := NewRegistry(32).Lookup(name)
+		assert.True(t, ok, name)
+
+		_, err := codec.Encode(input, make([]byte, 16))
+		assert.Equal(t, simple.ErrOutputTooShort, err, name)
+	}
+}
+
+func TestInteger_DecodeCorrupt(t *testing.T) {
+	params := []struct {
+		input       []byte
+		expectedErr error
+	}{
+		{[]byte{0xff, 0xff, 0xff, 0x7f}, simple.ErrCorruptInput},
+		{[]byte{0x00, 0x00, 0x00, 0x80}, simple.ErrCorruptInput},
+		{[]byte{0x01, 0x04, 0x00, 0x00}, simple.ErrCorruptInput},
+		{[]byte{0x00, 0x00, 0x00}, simple.ErrTruncatedInput},
+	}
+
+	for _, name := range []string{"bp32", "delta bp32", "fastpfor", "delta fastpfor"} {
+		codec, ok := NewRegistry(32).Lookup(name)
+		assert.True(t, ok, name)
+
+		for _, testCase := range params {
+			_, err := codec.Decode(testCase.input, nil)
+			assert.Equal(t, testCase.expectedErr, err, name)
+		}
+	}
+}
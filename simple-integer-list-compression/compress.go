@@ -9,13 +9,33 @@ const (
 	OrderDescending
 )
 
-type Compressor struct {
+type GenericCompressor[T Unsigned] struct {
 	ListOrder             int
 	CardinalityHeaderSize int
-	input                 []uint32
-	writer                *bitstream.Writer
+	Framed                bool
+	BlockSize             int
+	Delta                 bool
+	Forward               bool
+	Adaptive              bool
+	Hybrid                bool
+	Runs                  bool
+	FrameOfReference      bool
+	Strict                bool
+	Checksum              bool
+	// MaxCardinality is the bound of the decompressor the output is meant
+	// for, which refuses lists longer than it. Lists with runs are also
+	// bounded by DefaultMaxCardinality when MaxCardinality is zero.
+	MaxCardinality int
+	signed         bool
+	base           T
+	input          []T
+	writer         *bitstream.Writer
 }
 
+type Compressor = GenericCompressor[uint32]
+
+type Compressor64 = GenericCompressor[uint64]
+
 func NewCompressor(order int, cardHeaderSize int) *Compressor {
 	return &Compressor{
 		ListOrder:             order,
@@ -25,22 +45,55 @@ func NewCompressor(order int, cardHeaderSize int) *Compressor {
 	}
 }
 
-func (c *Compressor) isCardinalityHeaderSizeValid() bool {
-	return c.CardinalityHeaderSize >= 1 && c.CardinalityHeaderSize <= 32
+func NewCompressor64(order int, cardHeaderSize int) *Compressor64 {
+	return &Compressor64{
+		ListOrder:             order,
+		CardinalityHeaderSize: cardHeaderSize,
+		input:                 nil,
+		writer:                nil,
+	}
+}
+
+func isCardinalityHeaderSizeValid(size int) bool {
+	return size >= 1 && size <= 32
+}
+
+func (c *GenericCompressor[T]) isCardinalityHeaderSizeValid() bool {
+	return isCardinalityHeaderSizeValid(c.CardinalityHeaderSize)
 }
 
-func (c *Compressor) isInputSizeValid(size int) bool {
+func (c *GenericCompressor[T]) isInputSizeValid(size int) bool {
 	return size >= 0 && size < (1<<uint(c.CardinalityHeaderSize))
 }
 
-func (c *Compressor) writeCardinality() error {
+func (c *GenericCompressor[T]) checkOrder(input []T) error {
+	for i := 1; i < len(input); i++ {
+		if c.ListOrder == OrderAscending && input[i] < input[i-1] ||
+			c.ListOrder == OrderDescending && input[i] > input[i-1] {
+			return &UnsortedInputError{Index: i}
+		}
+	}
+	return nil
+}
+
+func (c *GenericCompressor[T]) writeCardinality() error {
 	return c.writer.Write(uint64(len(c.input)), c.CardinalityHeaderSize)
 }
 
-func (c *Compressor) writeValuesAsc() error {
-	w := 32
-	for i := len(c.input) - 1; i >= 0; i-- {
-		value := c.input[i]
+func (c *GenericCompressor[T]) writeHeader() error {
+	if err := c.writeCardinality(); err != nil {
+		return err
+	}
+	if c.hasBase() {
+		return c.writer.Write(uint64(c.base), wordSize[T]())
+	}
+	return nil
+}
+
+func (c *GenericCompressor[T]) writeValuesAsc(values []T) error {
+	w := wordSize[T]()
+	for i := len(values) - 1; i >= 0; i-- {
+		value := values[i] - c.base
 		if err := c.writer.Write(uint64(value), w); err != nil {
 			return err
 		}
@@ -49,9 +102,10 @@ func (c *Compressor) writeValuesAsc() error {
 	return nil
 }
 
-func (c *Compressor) writeValuesDesc() error {
-	w := 32
-	for _, value := range c.input {
+func (c *GenericCompressor[T]) writeValuesDesc(values []T) error {
+	w := wordSize[T]()
+	for _, v := range values {
+		value := v - c.base
 		if err := c.writer.Write(uint64(value), w); err != nil {
 			return err
 		}
@@ -60,14 +114,118 @@ func (c *Compressor) writeValuesDesc() error {
 	return nil
 }
 
-func (c *Compressor) writeValues() error {
+func (c *GenericCompressor[T]) encodeValues(values []T) error {
+	if c.hasContainers() {
+		return c.writeContainer(values)
+	}
+	return c.encodeArray(values)
+}
+
+func (c *GenericCompressor[T]) encodeArray(values []T) error {
+	if c.Delta {
+		return c.writeValuesDelta(values)
+	}
+	if c.isAdaptive() {
+		return c.writeValuesAdaptive(values)
+	}
+	if c.isForward() {
+		return c.writeValuesForward(values)
+	}
 	if c.ListOrder == OrderAscending {
-		return c.writeValuesAsc()
+		return c.writeValuesAsc(values)
+	}
+	return c.writeValuesDesc(values)
+}
+
+func (c *GenericCompressor[T]) encodedBitsLen(values []T) int {
+	if c.hasContainers() {
+		return c.containerBitsLen(values)
+	}
+	return c.arrayBitsLen(values)
+}
+
+func (c *GenericCompressor[T]) arrayBitsLen(values []T) int {
+	if c.Delta {
+		return deltaBitsLen(values, c.base, c.ListOrder)
+	}
+	if c.isAdaptive() {
+		return adaptiveBitsLen(values, c.base)
+	}
+	if c.isForward() {
+		return forwardBitsLen(values, c.base)
 	}
-	return c.writeValuesDesc()
+	return chainBitsLen(values, c.base, c.ListOrder)
 }
 
-func (c *Compressor) Compress(input []uint32, output []byte) (int, error) {
+func (c *GenericCompressor[T]) writeValues() error {
+	if c.BlockSize > 0 {
+		return c.writeBlocks()
+	}
+	return c.encodeValues(c.input)
+}
+
+func (c *GenericCompressor[T]) frameFlags() uint16 {
+	var flags uint16
+	if c.ListOrder == OrderDescending {
+		flags |= frameFlagDescending
+	}
+	if c.BlockSize > 0 {
+		flags |= frameFlagBlocked
+	}
+	if c.Delta {
+		flags |= frameFlagDelta
+	}
+	if wordSize[T]() == 64 {
+		flags |= frameFlag64
+	}
+	if c.signed {
+		flags |= frameFlagSigned
+	}
+	if c.FrameOfReference {
+		flags |= frameFlagFrameOfReference
+	}
+	if c.Checksum {
+		flags |= frameFlagChecksum
+	}
+	if c.isForward() {
+		flags |= frameFlagForward
+	}
+	if c.isAdaptive() {
+		flags |= frameFlagAdaptive
+	}
+	if c.Hybrid {
+		flags |= frameFlagHybrid
+	}
+	if c.Runs {
+		flags |= frameFlagRuns
+	}
+	return flags
+}
+
+func (c *GenericCompressor[T]) compressFramed(input []T, output []byte) (int, error) {
+	if len(output) < frameHeaderLen {
+		return 0, ErrOutputTooShort
+	}
+
+	payload := output[frameHeaderLen:]
+	n, err := c.compress(input, payload)
+	if err != nil {
+		return 0, err
+	}
+
+	payloadLen := sizeInBytes(n)
+	putFrameHeader(output, frameHeader{
+		version:               frameVersion,
+		flags:                 c.frameFlags(),
+		cardinalityHeaderSize: c.CardinalityHeaderSize,
+		payloadLen:            payloadLen,
+		checksum:              checksum(payload[:payloadLen]),
+	})
+
+	return 8*frameHeaderLen + n, nil
+}
+
+func (c *GenericCompressor[T]) Compress(input []T, output []byte) (int, error) {
 	if !c.isCardinalityHeaderSizeValid() {
 		return 0, ErrCardinalityHeaderSizeOutOfBound
 	}
@@ -76,10 +234,33 @@ func (c *Compressor) Compress(input []uint32, output []byte) (int, error) {
 		return 0, ErrInputTooLong
 	}
 
+	if !c.isBlockSizeValid() {
+		return 0, ErrBlockSizeOutOfBound
+	}
+
+	if c.Strict {
+		if err := c.checkOrder(input); err != nil {
+			return 0, err
+		}
+	}
+
+	if c.Framed {
+		return c.compressFramed(input, output)
+	}
+
+	return c.compress(input, output)
+}
+
+func (c *GenericCompressor[T]) compress(input []T, output []byte) (int, error) {
+	c.base = 0
+	if c.hasBase() {
+		c.base = minValue(input)
+	}
+
 	c.input = input
 	c.writer = bitstream.NewWriter(output)
 
-	if err := c.writeCardinality(); err != nil {
+	if err := c.writeHeader(); err != nil {
 		return 0, err
 	}
 
@@ -87,12 +268,75 @@ func (c *Compressor) Compress(input []uint32, output []byte) (int, error) {
 		return 0, err
 	}
 
+	if c.Checksum {
+		if err := c.writeChecksum(output); err != nil {
+			return 0, err
+		}
+	}
+
+	if !c.isCardinalityDecodable(8*sizeInBytes(c.writer.Offset()) - c.headerSize()) {
+		return 0, ErrCardinalityTooLarge
+	}
+
 	return c.writer.Offset(), nil
 }
 
-func (c *Compressor) MaxCompressedLen(n int) int {
-	if !c.isCardinalityHeaderSizeValid() || !c.isInputSizeValid(n) {
+func (c *GenericCompressor[T]) headerSize() int {
+	if c.hasBase() {
+		return c.CardinalityHeaderSize + wordSize[T]()
+	}
+	return c.CardinalityHeaderSize
+}
+
+// isCardinalityDecodable reports whether a decompressor bounded by
+// MaxCardinality accepts the input cardinality, given the number of bits
+// that follow the header. It mirrors the checks of readCardinality and
+// readHeader.
+func (c *GenericCompressor[T]) isCardinalityDecodable(bits int) bool {
+	n := len(c.input)
+	if c.MaxCardinality > 0 {
+		return n <= c.MaxCardinality
+	}
+	return !c.Runs || n <= bits || n <= DefaultMaxCardinality
+}
+
+func (c *GenericCompressor[T]) MaxCompressedLen(n int) int {
+	if !c.isCardinalityHeaderSizeValid() || !c.isInputSizeValid(n) || !c.isBlockSizeValid() {
 		return 0
 	}
-	return sizeInBytes(c.CardinalityHeaderSize + 32*n)
+	valueBits := wordSize[T]()
+	if c.Delta {
+		valueBits += 1 + deltaWidthHeaderSize[T]()
+	} else if c.isAdaptive() {
+		valueBits += gammaBitsLen(maxGamma[T]())
+	}
+	bits := c.CardinalityHeaderSize + valueBits*n
+	if c.FrameOfReference {
+		bits += wordSize[T]()
+	}
+	chains := 1
+	if c.BlockSize > 0 {
+		chains = numBlocks(n, c.BlockSize)
+	}
+	if c.isForward() {
+		// a unary 0 per value, and the width grows at most wordSize times
+		// per chain
+		bits += n + wordSize[T]()*chains
+	}
+	if c.hasContainers() {
+		// other containers are only used when smaller than arrays, so only
+		// the container code adds up
+		bits += chains
+	}
+	if c.BlockSize > 0 {
+		bits += blockSizeHeaderSize + blockEntrySize[T]()*numBlocks(n, c.BlockSize)
+	}
+	size := sizeInBytes(bits)
+	if c.Checksum {
+		size += checksumLen
+	}
+	if c.Framed {
+		size += frameHeaderLen
+	}
+	return size
 }
@@ -0,0 +1,155 @@
+package simple
+
+import (
+	"testing"
+
+	"github.com/vteromero/playground/simple-integer-list-compression/slice"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCompressor64_Compress(t *testing.T) {
+	params := []struct {
+		compressor     *Compressor64
+		input          []uint64
+		expectedN      int
+		expectedErr    error
+		expectedOutput []byte
+	}{
+		{NewCompressor64(OrderDescending, 0), []uint64{}, 0, ErrCardinalityHeaderSizeOutOfBound, []byte{}},
+		{NewCompressor64(OrderAscending, 2), []uint64{1, 2, 3, 4}, 0, ErrInputTooLong, []byte{}},
+		{NewCompressor64(OrderDescending, 8), []uint64{}, 8, nil, []byte{0x00}},
+		{NewCompressor64(OrderDescending, 8), []uint64{8888}, 72, nil, []byte{0x01, 0xb8, 0x22, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00}},
+		{NewCompressor64(OrderAscending, 8), []uint64{5, 111, 8888}, 93, nil, []byte{0x03, 0xb8, 0x22, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x6f, 0x40, 0x01}},
+		{NewCompressor64(OrderDescending, 8), []uint64{1 << 40, 5}, 8 + 64 + 41, nil, []byte{0x02, 0x00, 0x00, 0x00, 0x00, 0x00, 0x01, 0x00, 0x00, 0x05, 0x00, 0x00, 0x00, 0x00, 0x00}},
+	}
+
+	for _, testCase := range params {
+		n := len(testCase.input)
+		output := make([]byte, testCase.compressor.MaxCompressedLen(n))
+		m, err := testCase.compressor.Compress(testCase.input, output)
+		assert.Equal(t, testCase.expectedN, m)
+		assert.Equal(t, testCase.expectedErr, err)
+		assert.Equal(t, testCase.expectedOutput, output[:sizeInBytes(m)])
+	}
+}
+
+func TestCompressor64_MaxCompressedLen(t *testing.T) {
+	params := []struct {
+		compressor *Compressor64
+		n          int
+		expectedN  int
+	}{
+		{NewCompressor64(OrderAscending, 1), 0, 1},
+		{NewCompressor64(OrderAscending, 1), 1, 9},
+		{NewCompressor64(OrderDescending, 7), 100, 801},
+		{NewCompressor64(OrderAscending, 31), 10, 84},
+		{NewCompressor64(OrderAscending, 33), 100, 0},
+		{NewCompressor64(OrderAscending, 8), 256, 0},
+	}
+
+	for _, testCase := range params {
+		compLen := testCase.compressor.MaxCompressedLen(testCase.n)
+		assert.Equal(t, testCase.expectedN, compLen)
+	}
+}
+
+func TestCompressAndDecompress64(t *testing.T) {
+	params := []struct {
+		order                 int
+		cardinalityHeaderSize int
+		inputSize             int
+	}{
+		{OrderAscending, 8, 0},
+		{OrderAscending, 8, 10},
+		{OrderAscending, 8, 100},
+		{OrderAscending, 16, 0},
+		{OrderAscending, 16, 10},
+		{OrderAscending, 16, 100},
+		{OrderAscending, 16, 1000},
+		{OrderAscending, 32, 0},
+		{OrderAscending, 32, 10},
+		{OrderAscending, 32, 100},
+		{OrderAscending, 32, 1000},
+		{OrderDescending, 8, 0},
+		{OrderDescending, 8, 10},
+		{OrderDescending, 8, 100},
+		{OrderDescending, 16, 0},
+		{OrderDescending, 16, 10},
+		{OrderDescending, 16, 100},
+		{OrderDescending, 16, 1000},
+		{OrderDescending, 32, 0},
+		{OrderDescending, 32, 10},
+		{OrderDescending, 32, 100},
+		{OrderDescending, 32, 1000},
+	}
+
+	for _, testCase := range params {
+		var input []uint64
+		if testCase.order == OrderAscending {
+			input = slice.SortAscUint64Slice(slice.RandomUint64Slice(testCase.inputSize))
+		} else {
+			input = slice.SortDescUint64Slice(slice.RandomUint64Slice(testCase.inputSize))
+		}
+
+		c := NewCompressor64(testCase.order, testCase.cardinalityHeaderSize)
+		compOutput := make([]byte, c.MaxCompressedLen(testCase.inputSize))
+		_, err := c.Compress(input, compOutput)
+		assert.Nil(t, err)
+
+		d := NewDecompressor64(testCase.order, testCase.cardinalityHeaderSize)
+		output, err := d.Decompress(compOutput)
+		assert.Nil(t, err)
+
+		assert.Equal(t, input, output)
+	}
+}
+
+func TestCompressAndDecompress64Modes(t *testing.T) {
+	params := []struct {
+		order     int
+		delta     bool
+		blockSize int
+	}{
+		{OrderAscending, true, 0},
+		{OrderAscending, false, DefaultBlockSize},
+		{OrderAscending, true, DefaultBlockSize},
+		{OrderDescending, true, 0},
+		{OrderDescending, false, DefaultBlockSize},
+		{OrderDescending, true, DefaultBlockSize},
+	}
+
+	for _, testCase := range params {
+		var input []uint64
+		if testCase.order == OrderAscending {
+			input = slice.SortAscUint64Slice(slice.RandomUint64Slice(1000))
+		} else {
+			input = slice.SortDescUint64Slice(slice.RandomUint64Slice(1000))
+		}
+
+		c := NewCompressor64(testCase.order, 32)
+		c.Framed = true
+		c.Delta = testCase.delta
+		c.BlockSize = testCase.blockSize
+		compOutput := make([]byte, c.MaxCompressedLen(len(input)))
+		_, err := c.Compress(input, compOutput)
+		assert.Nil(t, err)
+
+		_, err = NewFramedDecompressor().Decompress(compOutput)
+		assert.Equal(t, ErrWordSizeMismatch, err)
+
+		d := NewFramedDecompressor64()
+		output, err := d.Decompress(compOutput)
+		assert.Nil(t, err)
+		assert.Equal(t, input, output)
+
+		if testCase.blockSize > 0 {
+			l, err := d.Open(compOutput)
+			assert.Nil(t, err)
+			for i, v := range input {
+				got, err := l.Get(i)
+				assert.Nil(t, err)
+				assert.Equal(t, v, got)
+			}
+		}
+	}
+}
@@ -43,6 +43,22 @@ func TestCompressor_Compress(t *testing.T) {
 	}
 }
 
+func TestCompressor_CompressFramed(t *testing.T) {
+	c := NewCompressor(OrderDescending, 8)
+	c.Framed = true
+	output := make([]byte, c.MaxCompressedLen(3))
+	m, err := c.Compress([]uint32{8888, 111, 5}, output)
+	assert.Nil(t, err)
+	assert.Equal(t, 8*frameHeaderLen+61, m)
+	assert.Equal(t, []byte{
+		'S', 'I', 'L', 'C', 0x02, 0x01, 0x00, 0x08, 0x08, 0x00, 0x00, 0x00, 0xfe, 0x8e, 0xa6, 0x76,
+		0x03, 0xb8, 0x22, 0x00, 0x00, 0x6f, 0x40, 0x01,
+	}, output[:sizeInBytes(m)])
+
+	_, err = c.Compress([]uint32{8888, 111, 5}, make([]byte, frameHeaderLen-1))
+	assert.Equal(t, ErrOutputTooShort, err)
+}
+
 func TestCompressor_MaxCompressedLen(t *testing.T) {
 	params := []struct {
 		compressor *Compressor
@@ -62,6 +78,11 @@ func TestCompressor_MaxCompressedLen(t *testing.T) {
 		{NewCompressor(OrderAscending, 2), 4, 0},
 		{NewCompressor(OrderAscending, 2), 100, 0},
 		{NewCompressor(OrderAscending, 8), 256, 0},
+		{&Compressor{ListOrder: OrderAscending, CardinalityHeaderSize: 8, Framed: true}, 0, 17},
+		{&Compressor{ListOrder: OrderAscending, CardinalityHeaderSize: 8, Framed: true}, 10, 57},
+		{&Compressor{ListOrder: OrderAscending, CardinalityHeaderSize: 8, Adaptive: true}, 10, 55},
+		{&Compressor{ListOrder: OrderAscending, CardinalityHeaderSize: 8, Hybrid: true}, 10, 42},
+		{&Compressor{ListOrder: OrderAscending, CardinalityHeaderSize: 8, Hybrid: true, Runs: true}, 10, 42},
 	}
 
 	for _, testCase := range params {
@@ -120,3 +141,90 @@ func TestCompressAndDecompress(t *testing.T) {
 		assert.Equal(t, input, output)
 	}
 }
+
+func TestCompressAndDecompressFramed(t *testing.T) {
+	params := []struct {
+		order                 int
+		cardinalityHeaderSize int
+		inputSize             int
+	}{
+		{OrderAscending, 8, 0},
+		{OrderAscending, 8, 100},
+		{OrderAscending, 16, 1000},
+		{OrderAscending, 32, 1000},
+		{OrderDescending, 8, 0},
+		{OrderDescending, 8, 100},
+		{OrderDescending, 16, 1000},
+		{OrderDescending, 32, 1000},
+	}
+
+	for _, testCase := range params {
+		var input []uint32
+		if testCase.order == OrderAscending {
+			input = slice.SortAscUint32Slice(slice.RandomUint32Slice(testCase.inputSize))
+		} else {
+			input = slice.SortDescUint32Slice(slice.RandomUint32Slice(testCase.inputSize))
+		}
+
+		c := NewCompressor(testCase.order, testCase.cardinalityHeaderSize)
+		c.Framed = true
+		compOutput := make([]byte, c.MaxCompressedLen(testCase.inputSize))
+		_, err := c.Compress(input, compOutput)
+		assert.Nil(t, err)
+
+		d := NewFramedDecompressor()
+		output, err := d.Decompress(compOutput)
+		assert.Nil(t, err)
+
+		assert.Equal(t, input, output)
+		assert.Equal(t, testCase.order, d.ListOrder)
+		assert.Equal(t, testCase.cardinalityHeaderSize, d.CardinalityHeaderSize)
+	}
+}
+
+func TestCompressor_CompressStrict(t *testing.T) {
+	params := []struct {
+		order         int
+		input         []uint32
+		expectedIndex int
+	}{
+		{OrderAscending, []uint32{}, -1},
+		{OrderAscending, []uint32{1}, -1},
+		{OrderAscending, []uint32{1, 1, 2, 3}, -1},
+		{OrderAscending, []uint32{2, 1}, 1},
+		{OrderAscending, []uint32{1, 2, 3, 100, 4, 5}, 4},
+		{OrderDescending, []uint32{3, 3, 2, 1}, -1},
+		{OrderDescending, []uint32{1, 2}, 1},
+		{OrderDescending, []uint32{100, 50, 60, 10}, 2},
+	}
+
+	for _, testCase := range params {
+		c := NewCompressor(testCase.order, 8)
+		c.Strict = true
+		output := make([]byte, c.MaxCompressedLen(len(testCase.input)))
+		_, err := c.Compress(testCase.input, output)
+
+		if testCase.expectedIndex < 0 {
+			assert.Nil(t, err)
+			continue
+		}
+
+		assert.ErrorIs(t, err, ErrUnsortedInput)
+		var unsortedErr *UnsortedInputError
+		if assert.ErrorAs(t, err, &unsortedErr) {
+			assert.Equal(t, testCase.expectedIndex, unsortedErr.Index)
+		}
+	}
+}
+
+func TestCompressor_CompressStrictSigned(t *testing.T) {
+	c := NewCompressor(OrderAscending, 8)
+	c.Strict = true
+	output := make([]byte, c.MaxCompressedSignedLen(4))
+
+	_, err := CompressInt32(c, []int32{-5, -1, 0, 7}, output)
+	assert.Nil(t, err)
+
+	_, err = CompressInt32(c, []int32{-5, 3, -1, 7}, output)
+	assert.Equal(t, &UnsortedInputError{Index: 2}, err)
+}
@@ -4,14 +4,39 @@ import (
 	"github.com/vteromero/bitstream"
 )
 
-type Decompressor struct {
+// GenericDecompressor decodes lists written by GenericCompressor. Apart from
+// lists with runs, it never allocates more values than the input has bits.
+// Lists with runs are bounded by MaxCardinality, or by DefaultMaxCardinality
+// when it is not set, so decoding untrusted input allocates up to 256 KiB
+// with a Decompressor and 512 KiB with a Decompressor64 by default.
+type GenericDecompressor[T Unsigned] struct {
 	ListOrder             int
 	CardinalityHeaderSize int
-	cardinality           int
-	input                 []byte
-	reader                *bitstream.Reader
+	Framed                bool
+	Blocked               bool
+	Delta                 bool
+	Forward               bool
+	Adaptive              bool
+	Hybrid                bool
+	// Runs lists can hold more values than they take bits, so their
+	// cardinality is bounded by MaxCardinality, or by DefaultMaxCardinality
+	// when MaxCardinality is zero.
+	Runs             bool
+	FrameOfReference bool
+	Checksum         bool
+	MaxCardinality   int
+	signed           bool
+	base             T
+	cardinality      int
+	input            []byte
+	reader           *bitstream.Reader
+	offset           int
 }
 
+type Decompressor = GenericDecompressor[uint32]
+
+type Decompressor64 = GenericDecompressor[uint64]
+
 func NewDecompressor(order int, cardHeaderSize int) *Decompressor {
 	return &Decompressor{
 		ListOrder:             order,
@@ -22,61 +47,272 @@ func NewDecompressor(order int, cardHeaderSize int) *Decompressor {
 	}
 }
 
-func (d *Decompressor) readCardinality() error {
-	v, err := d.reader.Read(d.CardinalityHeaderSize)
+func NewDecompressor64(order int, cardHeaderSize int) *Decompressor64 {
+	return &Decompressor64{
+		ListOrder:             order,
+		CardinalityHeaderSize: cardHeaderSize,
+		cardinality:           0,
+		input:                 nil,
+		reader:                nil,
+	}
+}
+
+func NewFramedDecompressor() *Decompressor {
+	return &Decompressor{
+		Framed: true,
+	}
+}
+
+func NewFramedDecompressor64() *Decompressor64 {
+	return &Decompressor64{
+		Framed: true,
+	}
+}
+
+func (d *GenericDecompressor[T]) readFrame(input []byte) ([]byte, error) {
+	h, err := parseFrameHeader(input)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(input)-frameHeaderLen < h.payloadLen {
+		return nil, ErrTruncatedInput
+	}
+
+	if h.wordSize() != wordSize[T]() {
+		return nil, ErrWordSizeMismatch
+	}
+
+	if (h.flags&frameFlagSigned != 0) != d.signed {
+		return nil, ErrSignednessMismatch
+	}
+
+	payload := input[frameHeaderLen : frameHeaderLen+h.payloadLen]
+	if checksum(payload) != h.checksum {
+		return nil, ErrChecksumMismatch
+	}
+
+	d.ListOrder = h.listOrder()
+	d.Blocked = h.flags&frameFlagBlocked != 0
+	d.Delta = h.flags&frameFlagDelta != 0
+	d.Forward = h.flags&frameFlagForward != 0
+	d.Adaptive = h.flags&frameFlagAdaptive != 0
+	d.Hybrid = h.flags&frameFlagHybrid != 0
+	d.Runs = h.flags&frameFlagRuns != 0
+	d.FrameOfReference = h.flags&frameFlagFrameOfReference != 0
+	d.Checksum = h.flags&frameFlagChecksum != 0
+	d.CardinalityHeaderSize = h.cardinalityHeaderSize
+
+	return payload, nil
+}
+
+// read reads nbits from the input, reporting any failure as truncated
+// input since that is the only way the reader can fail.
+func (d *GenericDecompressor[T]) read(nbits int) (uint64, error) {
+	v, err := d.reader.Read(nbits)
+	if err != nil {
+		return 0, ErrTruncatedInput
+	}
+	d.offset += nbits
+	return v, nil
+}
+
+// seek positions the reader at the given bit offset of the input.
+func (d *GenericDecompressor[T]) seek(offset int) error {
+	if offset < 0 || offset > 8*len(d.input) {
+		return ErrTruncatedInput
+	}
+
+	d.reader = bitstream.NewReader(d.input[offset/8:])
+	d.offset = offset - offset%8
+	if offset%8 > 0 {
+		if _, err := d.read(offset % 8); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (d *GenericDecompressor[T]) remainingBits() int {
+	return 8*len(d.input) - d.offset
+}
+
+func (d *GenericDecompressor[T]) readCardinality() error {
+	v, err := d.read(d.CardinalityHeaderSize)
+	if err != nil {
+		return err
+	}
+
 	d.cardinality = int(v)
-	return err
+
+	if d.MaxCardinality > 0 && d.cardinality > d.MaxCardinality {
+		return ErrCardinalityTooLarge
+	}
+
+	return nil
 }
 
-func (d *Decompressor) readValuesAsc(output []uint32) ([]uint32, error) {
-	w := 32
+func (d *GenericDecompressor[T]) readHeader() error {
+	if err := d.readCardinality(); err != nil {
+		return err
+	}
 
-	for i := d.cardinality - 1; i >= 0; i-- {
-		v, err := d.reader.Read(w)
+	d.base = 0
+	if d.hasBase() {
+		v, err := d.read(wordSize[T]())
+		if err != nil {
+			return err
+		}
+		d.base = T(v)
+	}
+
+	// every value takes at least one bit, unless it is part of a run, in
+	// which case MaxCardinality or DefaultMaxCardinality bounds the
+	// cardinality
+	if d.cardinality > d.remainingBits() {
+		if !d.Runs {
+			return ErrTruncatedInput
+		}
+		if d.MaxCardinality == 0 && d.cardinality > DefaultMaxCardinality {
+			return ErrCardinalityTooLarge
+		}
+	}
+
+	return nil
+}
+
+func (d *GenericDecompressor[T]) headerSize() int {
+	if d.hasBase() {
+		return d.CardinalityHeaderSize + wordSize[T]()
+	}
+	return d.CardinalityHeaderSize
+}
+
+func (d *GenericDecompressor[T]) readValuesAsc(output []T) ([]T, error) {
+	w := wordSize[T]()
+
+	for i := len(output) - 1; i >= 0; i-- {
+		v, err := d.read(w)
 		if err != nil {
 			return nil, err
 		}
 
-		output[i] = uint32(v)
+		output[i] = T(v)
 
-		w = bitsLen(uint32(v))
+		w = bitsLen(T(v))
 	}
 
 	return output, nil
 }
 
-func (d *Decompressor) readValuesDesc(output []uint32) ([]uint32, error) {
-	w := 32
+func (d *GenericDecompressor[T]) readValuesDesc(output []T) ([]T, error) {
+	w := wordSize[T]()
 
-	for i := 0; i < d.cardinality; i++ {
-		v, err := d.reader.Read(w)
+	for i := 0; i < len(output); i++ {
+		v, err := d.read(w)
 		if err != nil {
 			return nil, err
 		}
 
-		output[i] = uint32(v)
+		output[i] = T(v)
 
-		w = bitsLen(uint32(v))
+		w = bitsLen(T(v))
 	}
 
 	return output, nil
 }
 
-func (d *Decompressor) readValues() ([]uint32, error) {
-	values := make([]uint32, d.cardinality)
+func (d *GenericDecompressor[T]) decodeValues(output []T) ([]T, error) {
+	if d.hasContainers() {
+		return d.readContainer(output)
+	}
+	return d.decodeArray(output)
+}
+
+func (d *GenericDecompressor[T]) decodeArray(output []T) ([]T, error) {
+	if d.Delta {
+		return d.readValuesDelta(output)
+	}
+	if d.isAdaptive() {
+		return d.readValuesAdaptive(output)
+	}
+	if d.isForward() {
+		return d.readValuesForward(output)
+	}
 	if d.ListOrder == OrderAscending {
-		return d.readValuesAsc(values)
+		return d.readValuesAsc(output)
+	}
+	return d.readValuesDesc(output)
+}
+
+func (d *GenericDecompressor[T]) readValues(values []T) ([]T, error) {
+	if d.Blocked {
+		if _, err := d.readBlocks(values); err != nil {
+			return nil, err
+		}
+	} else {
+		if _, err := d.decodeValues(values); err != nil {
+			return nil, err
+		}
+		d.addBase(values)
+	}
+
+	if d.Checksum {
+		if err := verifyChecksum(d.input, d.offset); err != nil {
+			return nil, err
+		}
 	}
-	return d.readValuesDesc(values)
+
+	return values, nil
 }
 
-func (d *Decompressor) Decompress(input []byte) ([]uint32, error) {
+func (d *GenericDecompressor[T]) begin(input []byte) error {
+	if d.Framed {
+		payload, err := d.readFrame(input)
+		if err != nil {
+			return err
+		}
+		input = payload
+	}
+
+	if !isCardinalityHeaderSizeValid(d.CardinalityHeaderSize) {
+		return ErrCardinalityHeaderSizeOutOfBound
+	}
+
 	d.input = input
 	d.reader = bitstream.NewReader(input)
+	d.offset = 0
 
-	if err := d.readCardinality(); err != nil {
+	return d.readHeader()
+}
+
+// Cardinality returns the number of values encoded in input without
+// decoding them.
+func (d *GenericDecompressor[T]) Cardinality(input []byte) (int, error) {
+	if err := d.begin(input); err != nil {
+		return 0, err
+	}
+	return d.cardinality, nil
+}
+
+func (d *GenericDecompressor[T]) Decompress(input []byte) ([]T, error) {
+	return d.DecompressInto(input, nil)
+}
+
+// DecompressInto is like Decompress but decodes into dst when it has enough
+// capacity, so that the values are not allocated. The bit reader over input
+// still is, once per call.
+func (d *GenericDecompressor[T]) DecompressInto(input []byte, dst []T) ([]T, error) {
+	if err := d.begin(input); err != nil {
 		return nil, err
 	}
 
-	return d.readValues()
+	var values []T
+	if dst != nil && cap(dst) >= d.cardinality {
+		values = dst[:d.cardinality]
+	} else {
+		values = make([]T, d.cardinality)
+	}
+
+	return d.readValues(values)
 }
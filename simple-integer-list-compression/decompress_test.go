@@ -3,6 +3,7 @@ package simple
 import (
 	"testing"
 
+	"github.com/vteromero/playground/simple-integer-list-compression/slice"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -31,3 +32,194 @@ func TestDecompressor_Decompress(t *testing.T) {
 		assert.Equal(t, testCase.expectedOutput, output)
 	}
 }
+
+func TestDecompressor_DecompressFramed(t *testing.T) {
+	valid := []byte{
+		'S', 'I', 'L', 'C', 0x02, 0x01, 0x00, 0x08, 0x08, 0x00, 0x00, 0x00, 0xfe, 0x8e, 0xa6, 0x76,
+		0x03, 0xb8, 0x22, 0x00, 0x00, 0x6f, 0x40, 0x01,
+	}
+
+	badMagic := append([]byte{}, valid...)
+	badMagic[0] = 'X'
+
+	badVersion := append([]byte{}, valid...)
+	badVersion[4] = 0x03
+
+	// the layout of version 1, with one byte of flags
+	version1 := []byte{
+		'S', 'I', 'L', 'C', 0x01, 0x01, 0x08, 0x08, 0x00, 0x00, 0x00, 0xfe, 0x8e, 0xa6, 0x76,
+		0x03, 0xb8, 0x22, 0x00, 0x00, 0x6f, 0x40, 0x01,
+	}
+
+	unknownFlag := append([]byte{}, valid...)
+	unknownFlag[6] |= 0x80
+
+	badHeaderSize := append([]byte{}, valid...)
+	badHeaderSize[7] = 33
+
+	badChecksum := append([]byte{}, valid...)
+	badChecksum[len(badChecksum)-1] ^= 0xff
+
+	params := []struct {
+		input          []byte
+		expectedErr    error
+		expectedOutput []uint32
+	}{
+		{valid, nil, []uint32{8888, 111, 5}},
+		{valid[:4], ErrTruncatedInput, nil},
+		{valid[:10], ErrTruncatedInput, nil},
+		{valid[:20], ErrTruncatedInput, nil},
+		{badMagic, ErrInvalidFrame, nil},
+		{badVersion, ErrUnsupportedVersion, nil},
+		{version1, ErrUnsupportedVersion, nil},
+		{unknownFlag, ErrUnsupportedVersion, nil},
+		{badHeaderSize, ErrInvalidFrame, nil},
+		{badChecksum, ErrChecksumMismatch, nil},
+	}
+
+	for _, testCase := range params {
+		output, err := NewFramedDecompressor().Decompress(testCase.input)
+		assert.Equal(t, testCase.expectedErr, err)
+		assert.Equal(t, testCase.expectedOutput, output)
+	}
+}
+
+func TestDecompressor_Cardinality(t *testing.T) {
+	params := []struct {
+		decompressor        *Decompressor
+		input               []byte
+		expectedErr         error
+		expectedCardinality int
+	}{
+		{NewDecompressor(OrderDescending, 8), []byte{0x00}, nil, 0},
+		{NewDecompressor(OrderDescending, 8), []byte{0x03, 0xb8, 0x22, 0x00, 0x00, 0x6f, 0x40, 0x01}, nil, 3},
+		{NewDecompressor(OrderDescending, 4), []byte{0x03, 0xb8, 0x22, 0x00, 0x00, 0x6f, 0x40, 0x01}, nil, 3},
+		{NewFramedDecompressor(), []byte{
+			'S', 'I', 'L', 'C', 0x02, 0x01, 0x00, 0x08, 0x08, 0x00, 0x00, 0x00, 0xfe, 0x8e, 0xa6, 0x76,
+			0x03, 0xb8, 0x22, 0x00, 0x00, 0x6f, 0x40, 0x01,
+		}, nil, 3},
+		{NewFramedDecompressor(), []byte{0x03}, ErrInvalidFrame, 0},
+	}
+
+	for _, testCase := range params {
+		cardinality, err := testCase.decompressor.Cardinality(testCase.input)
+		assert.Equal(t, testCase.expectedErr, err)
+		assert.Equal(t, testCase.expectedCardinality, cardinality)
+	}
+}
+
+func TestDecompressor_DecompressInto(t *testing.T) {
+	input := []byte{0x03, 0xb8, 0x22, 0x00, 0x00, 0x6f, 0x40, 0x01}
+	d := NewDecompressor(OrderAscending, 8)
+
+	dst := make([]uint32, 0, 10)
+	output, err := d.DecompressInto(input, dst)
+	assert.Nil(t, err)
+	assert.Equal(t, []uint32{5, 111, 8888}, output)
+	assert.Equal(t, &dst[:1][0], &output[0])
+
+	small := make([]uint32, 2)
+	output, err = d.DecompressInto(input, small)
+	assert.Nil(t, err)
+	assert.Equal(t, []uint32{5, 111, 8888}, output)
+	assert.Equal(t, []uint32{0, 0}, small)
+
+	// only the bit reader is allocated
+	allocs := testing.AllocsPerRun(100, func() {
+		d.DecompressInto(input, dst)
+	})
+	assert.LessOrEqual(t, allocs, 1.0)
+}
+
+func TestDecompressor_DecompressMalformed(t *testing.T) {
+	limited := NewDecompressor(OrderDescending, 8)
+	limited.MaxCardinality = 2
+
+	blocked := NewDecompressor(OrderDescending, 8)
+	blocked.Blocked = true
+
+	params := []struct {
+		decompressor *Decompressor
+		input        []byte
+		expectedErr  error
+	}{
+		{NewDecompressor(OrderDescending, 0), []byte{0x00}, ErrCardinalityHeaderSizeOutOfBound},
+		{NewDecompressor(OrderDescending, 33), []byte{0x00}, ErrCardinalityHeaderSizeOutOfBound},
+		{NewDecompressor(OrderDescending, 8), []byte{}, ErrTruncatedInput},
+		{NewDecompressor(OrderDescending, 32), []byte{0xff, 0xff, 0xff, 0xff}, ErrTruncatedInput},
+		{NewDecompressor(OrderDescending, 8), []byte{0x03, 0xb8, 0x22, 0x00, 0x00, 0x6f}, ErrTruncatedInput},
+		{NewDecompressor(OrderAscending, 8), []byte{0x03, 0xb8, 0x22, 0x00, 0x00, 0x6f}, ErrTruncatedInput},
+		{limited, []byte{0x03, 0xb8, 0x22, 0x00, 0x00, 0x6f, 0x40, 0x01}, ErrCardinalityTooLarge},
+		{blocked, []byte{0x01, 0x00, 0x00}, ErrCorruptInput},
+		{blocked, []byte{0x01, 0x80, 0x00, 0x00}, ErrTruncatedInput},
+	}
+
+	for _, testCase := range params {
+		output, err := testCase.decompressor.Decompress(testCase.input)
+		assert.Equal(t, testCase.expectedErr, err)
+		assert.Nil(t, output)
+	}
+}
+
+func TestCompressAndDecompressChecksum(t *testing.T) {
+	params := []struct {
+		order     int
+		inputSize int
+		blockSize int
+		framed    bool
+	}{
+		{OrderAscending, 0, 0, false},
+		{OrderAscending, 1000, 0, false},
+		{OrderAscending, 1000, DefaultBlockSize, false},
+		{OrderAscending, 1000, DefaultBlockSize, true},
+		{OrderDescending, 0, 0, false},
+		{OrderDescending, 1000, 0, false},
+		{OrderDescending, 1000, DefaultBlockSize, false},
+		{OrderDescending, 1000, DefaultBlockSize, true},
+	}
+
+	for _, testCase := range params {
+		var input []uint32
+		if testCase.order == OrderAscending {
+			input = slice.SortAscUint32Slice(slice.RandomUint32Slice(testCase.inputSize))
+		} else {
+			input = slice.SortDescUint32Slice(slice.RandomUint32Slice(testCase.inputSize))
+		}
+
+		c := NewCompressor(testCase.order, 32)
+		c.Checksum = true
+		c.BlockSize = testCase.blockSize
+		c.Framed = testCase.framed
+		compOutput := make([]byte, c.MaxCompressedLen(testCase.inputSize))
+		n, err := c.Compress(input, compOutput)
+		assert.Nil(t, err)
+
+		var d *Decompressor
+		if testCase.framed {
+			d = NewFramedDecompressor()
+		} else {
+			d = NewDecompressor(testCase.order, 32)
+			d.Checksum = true
+			d.Blocked = testCase.blockSize > 0
+		}
+		output, err := d.Decompress(compOutput)
+		assert.Nil(t, err)
+		assert.Equal(t, input, output)
+
+		if testCase.framed {
+			continue
+		}
+
+		_, err = d.Decompress(compOutput[:sizeInBytes(n)-1])
+		assert.Equal(t, ErrTruncatedInput, err)
+
+		compOutput[sizeInBytes(n)-1] ^= 0x01
+		_, err = d.Decompress(compOutput)
+		assert.Equal(t, ErrChecksumMismatch, err)
+
+		if testCase.blockSize > 0 {
+			_, err = d.Open(compOutput)
+			assert.Equal(t, ErrChecksumMismatch, err)
+		}
+	}
+}
@@ -0,0 +1,132 @@
+package simple
+
+// In delta mode the first value is written at full width and every
+// following value is replaced by its gap to the previous one (v[i]-v[i-1]
+// for ascending lists, v[i-1]-v[i] for descending ones). Gaps are not
+// ordered, so each one is preceded by a flag bit: 0 means the gap fits in
+// the width of the previous element and is written at that width, 1 means
+// it is followed by its own width (minus one) in deltaWidthHeaderSize bits
+// and then the gap at that width.
+func deltaWidthHeaderSize[T Unsigned]() int {
+	return bitsLen(uint32(wordSize[T]() - 1))
+}
+
+func gap[T Unsigned](prev, value T, order int) T {
+	if order == OrderAscending {
+		return value - prev
+	}
+	return prev - value
+}
+
+func applyGap[T Unsigned](prev, g T, order int) T {
+	if order == OrderAscending {
+		return prev + g
+	}
+	return prev - g
+}
+
+func deltaBitsLen[T Unsigned](values []T, base T, order int) int {
+	if len(values) == 0 {
+		return 0
+	}
+
+	bits := wordSize[T]()
+	w := bitsLen(values[0] - base)
+	for i := 1; i < len(values); i++ {
+		g := gap(values[i-1], values[i], order)
+		gw := bitsLen(g)
+		if gw <= w {
+			bits += 1 + w
+		} else {
+			bits += 1 + deltaWidthHeaderSize[T]() + gw
+		}
+		w = gw
+	}
+	return bits
+}
+
+func (c *GenericCompressor[T]) writeValuesDelta(values []T) error {
+	if len(values) == 0 {
+		return nil
+	}
+
+	first := values[0] - c.base
+	if err := c.writer.Write(uint64(first), wordSize[T]()); err != nil {
+		return err
+	}
+
+	w := bitsLen(first)
+	for i := 1; i < len(values); i++ {
+		g := gap(values[i-1], values[i], c.ListOrder)
+		gw := bitsLen(g)
+
+		if gw <= w {
+			if err := c.writer.Write(0, 1); err != nil {
+				return err
+			}
+		} else {
+			if err := c.writer.Write(1, 1); err != nil {
+				return err
+			}
+			if err := c.writer.Write(uint64(gw-1), deltaWidthHeaderSize[T]()); err != nil {
+				return err
+			}
+			w = gw
+		}
+
+		if err := c.writer.Write(uint64(g), w); err != nil {
+			return err
+		}
+		w = gw
+	}
+
+	return nil
+}
+
+func (d *GenericDecompressor[T]) readValuesDelta(output []T) ([]T, error) {
+	if len(output) == 0 {
+		return output, nil
+	}
+
+	v, err := d.read(wordSize[T]())
+	if err != nil {
+		return nil, err
+	}
+	output[0] = T(v)
+
+	w := bitsLen(output[0])
+	for i := 1; i < len(output); i++ {
+		g, err := d.readGap(w)
+		if err != nil {
+			return nil, err
+		}
+
+		output[i] = applyGap(output[i-1], g, d.ListOrder)
+		w = bitsLen(g)
+	}
+
+	return output, nil
+}
+
+// readGap reads the next gap, given the width w of the previous one.
+func (d *GenericDecompressor[T]) readGap(w int) (T, error) {
+	escape, err := d.read(1)
+	if err != nil {
+		return 0, err
+	}
+
+	if escape == 1 {
+		gw, err := d.read(deltaWidthHeaderSize[T]())
+		if err != nil {
+			return 0, err
+		}
+		w = int(gw) + 1
+	}
+
+	g, err := d.read(w)
+	if err != nil {
+		return 0, err
+	}
+
+	return T(g), nil
+}
@@ -0,0 +1,103 @@
+package simple
+
+import (
+	"testing"
+
+	"github.com/vteromero/playground/simple-integer-list-compression/slice"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCompressor_CompressDelta(t *testing.T) {
+	params := []struct {
+		order     int
+		input     []uint32
+		expectedN int
+	}{
+		{OrderAscending, []uint32{}, 8},
+		{OrderAscending, []uint32{8888}, 40},
+		{OrderAscending, []uint32{5, 111, 8888}, 73},
+		{OrderAscending, []uint32{1000, 1001, 1002, 1003}, 40 + (1 + 10) + 2*(1+1)},
+		{OrderAscending, []uint32{7, 7, 7}, 40 + (1 + 3) + (1 + 1)},
+		{OrderDescending, []uint32{8888, 111, 5}, 40 + (1 + 14) + (1 + 14)},
+	}
+
+	for _, testCase := range params {
+		c := NewCompressor(testCase.order, 8)
+		c.Delta = true
+		output := make([]byte, c.MaxCompressedLen(len(testCase.input)))
+		m, err := c.Compress(testCase.input, output)
+		assert.Nil(t, err)
+		assert.Equal(t, testCase.expectedN, m)
+
+		d := NewDecompressor(testCase.order, 8)
+		d.Delta = true
+		decompressed, err := d.Decompress(output)
+		assert.Nil(t, err)
+		assert.Equal(t, testCase.input, decompressed)
+	}
+}
+
+func TestCompressAndDecompressDelta(t *testing.T) {
+	params := []struct {
+		order     int
+		inputSize int
+		blockSize int
+		framed    bool
+	}{
+		{OrderAscending, 0, 0, false},
+		{OrderAscending, 10, 0, false},
+		{OrderAscending, 1000, 0, false},
+		{OrderAscending, 1000, 0, true},
+		{OrderAscending, 1000, DefaultBlockSize, false},
+		{OrderAscending, 1000, DefaultBlockSize, true},
+		{OrderDescending, 0, 0, false},
+		{OrderDescending, 10, 0, false},
+		{OrderDescending, 1000, 0, false},
+		{OrderDescending, 1000, 0, true},
+		{OrderDescending, 1000, DefaultBlockSize, false},
+		{OrderDescending, 1000, DefaultBlockSize, true},
+	}
+
+	for _, testCase := range params {
+		var input []uint32
+		if testCase.order == OrderAscending {
+			input = slice.SortAscUint32Slice(slice.RandomUint32Slice(testCase.inputSize))
+		} else {
+			input = slice.SortDescUint32Slice(slice.RandomUint32Slice(testCase.inputSize))
+		}
+
+		c := NewCompressor(testCase.order, 32)
+		c.Delta = true
+		c.BlockSize = testCase.blockSize
+		c.Framed = testCase.framed
+		compOutput := make([]byte, c.MaxCompressedLen(testCase.inputSize))
+		_, err := c.Compress(input, compOutput)
+		assert.Nil(t, err)
+
+		var d *Decompressor
+		if testCase.framed {
+			d = NewFramedDecompressor()
+		} else {
+			d = NewDecompressor(testCase.order, 32)
+			d.Delta = true
+			d.Blocked = testCase.blockSize > 0
+		}
+		output, err := d.Decompress(compOutput)
+		assert.Nil(t, err)
+		assert.Equal(t, input, output)
+	}
+}
+
+func TestCompressDeltaIsSmallerOnSortedInput(t *testing.T) {
+	input := slice.SortAscUint32Slice(slice.RandomUint32Slice(10000))
+
+	c := NewCompressor(OrderAscending, 32)
+	n, err := c.Compress(input, make([]byte, c.MaxCompressedLen(len(input))))
+	assert.Nil(t, err)
+
+	c.Delta = true
+	deltaN, err := c.Compress(input, make([]byte, c.MaxCompressedLen(len(input))))
+	assert.Nil(t, err)
+
+	assert.Less(t, deltaN, n)
+}
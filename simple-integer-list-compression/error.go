@@ -1,8 +1,41 @@
 package simple
 
-import "errors"
+import (
+	"errors"
+	"fmt"
+)
 
 var (
 	ErrCardinalityHeaderSizeOutOfBound = errors.New("simple: CardinalityHeaderSize out of bound")
 	ErrInputTooLong                    = errors.New("simple: input too long")
+	ErrUnsortedInput                   = errors.New("simple: input not sorted")
+	ErrBlockSizeOutOfBound             = errors.New("simple: BlockSize out of bound")
+	ErrNotBlocked                      = errors.New("simple: input is not blocked")
+	ErrIndexOutOfRange                 = errors.New("simple: index out of range")
+	ErrOutputTooShort                  = errors.New("simple: output too short")
+	ErrInvalidFrame                    = errors.New("simple: invalid frame")
+	ErrUnsupportedVersion              = errors.New("simple: unsupported frame version")
+	ErrChecksumMismatch                = errors.New("simple: checksum mismatch")
+	ErrCorruptInput                    = errors.New("simple: corrupt input")
+	ErrTruncatedInput                  = errors.New("simple: truncated input")
+	ErrCardinalityTooLarge             = errors.New("simple: cardinality too large")
+	ErrWordSizeMismatch                = errors.New("simple: word size mismatch")
+	ErrSignednessMismatch              = errors.New("simple: signedness mismatch")
+	ErrInvalidChunk                    = errors.New("simple: invalid stream chunk")
+	ErrChunkTooLarge                   = errors.New("simple: stream chunk too large")
+	ErrWriterClosed                    = errors.New("simple: writer closed")
 )
+
+// UnsortedInputError is returned in strict mode when the input does not
+// follow ListOrder. Index is the position of the first offending value.
+type UnsortedInputError struct {
+	Index int
+}
+
+func (e *UnsortedInputError) Error() string {
+	return fmt.Sprintf("%s: at index %d", ErrUnsortedInput, e.Index)
+}
+
+func (e *UnsortedInputError) Is(target error) bool {
+	return target == ErrUnsortedInput
+}
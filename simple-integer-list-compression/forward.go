@@ -0,0 +1,104 @@
+package simple
+
+// The forward layout lets ascending lists be decoded front-to-back. The
+// first value is written at full width and every following value at the
+// width of the largest value seen so far, preceded by the growth of that
+// width in unary (as many 1 bits as the width grows, then a 0 bit). On
+// ascending input the width is just the value's own width.
+func forwardBitsLen[T Unsigned](values []T, base T) int {
+	if len(values) == 0 {
+		return 0
+	}
+
+	bits := wordSize[T]()
+	w := bitsLen(values[0] - base)
+	for _, v := range values[1:] {
+		vw := max(bitsLen(v-base), w)
+		bits += 1 + vw - w + vw
+		w = vw
+	}
+	return bits
+}
+
+func (c *GenericCompressor[T]) isForward() bool {
+	return c.Forward && c.ListOrder == OrderAscending && !c.Delta && !c.Adaptive
+}
+
+func (d *GenericDecompressor[T]) isForward() bool {
+	return d.Forward && d.ListOrder == OrderAscending && !d.Delta && !d.Adaptive
+}
+
+func (c *GenericCompressor[T]) writeValuesForward(values []T) error {
+	if len(values) == 0 {
+		return nil
+	}
+
+	first := values[0] - c.base
+	if err := c.writer.Write(uint64(first), wordSize[T]()); err != nil {
+		return err
+	}
+
+	w := bitsLen(first)
+	for _, v := range values[1:] {
+		v -= c.base
+		vw := max(bitsLen(v), w)
+		for ; w < vw; w++ {
+			if err := c.writer.Write(1, 1); err != nil {
+				return err
+			}
+		}
+		if err := c.writer.Write(0, 1); err != nil {
+			return err
+		}
+		if err := c.writer.Write(uint64(v), w); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// readForwardWidth reads the unary width growth and returns the width of
+// the next value, given the width w of the previous one.
+func (d *GenericDecompressor[T]) readForwardWidth(w int) (int, error) {
+	for {
+		bit, err := d.read(1)
+		if err != nil {
+			return 0, err
+		}
+		if bit == 0 {
+			return w, nil
+		}
+		w++
+		if w > wordSize[T]() {
+			return 0, ErrCorruptInput
+		}
+	}
+}
+
+func (d *GenericDecompressor[T]) readValuesForward(output []T) ([]T, error) {
+	if len(output) == 0 {
+		return output, nil
+	}
+
+	v, err := d.read(wordSize[T]())
+	if err != nil {
+		return nil, err
+	}
+	output[0] = T(v)
+
+	w := bitsLen(output[0])
+	for i := 1; i < len(output); i++ {
+		if w, err = d.readForwardWidth(w); err != nil {
+			return nil, err
+		}
+
+		v, err := d.read(w)
+		if err != nil {
+			return nil, err
+		}
+		output[i] = T(v)
+	}
+
+	return output, nil
+}
@@ -0,0 +1,122 @@
+package simple
+
+import (
+	"testing"
+
+	"github.com/vteromero/playground/simple-integer-list-compression/slice"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCompressor_CompressForward(t *testing.T) {
+	params := []struct {
+		input     []uint32
+		expectedN int
+	}{
+		{[]uint32{}, 8},
+		{[]uint32{8888}, 40},
+		{[]uint32{5, 111, 8888}, 40 + (1 + 4 + 7) + (1 + 7 + 14)},
+		{[]uint32{1000, 1001, 1002, 1003}, 40 + 3*(1+10)},
+		{[]uint32{7, 7, 7}, 40 + 2*(1+3)},
+		{[]uint32{8888, 5, 111}, 40 + 2*(1+14)},
+	}
+
+	for _, testCase := range params {
+		c := NewCompressor(OrderAscending, 8)
+		c.Forward = true
+		output := make([]byte, c.MaxCompressedLen(len(testCase.input)))
+		m, err := c.Compress(testCase.input, output)
+		assert.Nil(t, err)
+		assert.Equal(t, testCase.expectedN, m)
+
+		d := NewDecompressor(OrderAscending, 8)
+		d.Forward = true
+		decompressed, err := d.Decompress(output)
+		assert.Nil(t, err)
+		assert.Equal(t, testCase.input, decompressed)
+	}
+}
+
+func TestCompressAndDecompressForward(t *testing.T) {
+	params := []struct {
+		inputSize int
+		blockSize int
+		framed    bool
+	}{
+		{0, 0, false},
+		{10, 0, false},
+		{1000, 0, false},
+		{1000, 0, true},
+		{1000, DefaultBlockSize, false},
+		{1000, DefaultBlockSize, true},
+	}
+
+	for _, testCase := range params {
+		input := slice.SortAscUint32Slice(slice.RandomUint32Slice(testCase.inputSize))
+
+		c := NewCompressor(OrderAscending, 32)
+		c.Forward = true
+		c.BlockSize = testCase.blockSize
+		c.Framed = testCase.framed
+		compOutput := make([]byte, c.MaxCompressedLen(testCase.inputSize))
+		_, err := c.Compress(input, compOutput)
+		assert.Nil(t, err)
+
+		var d *Decompressor
+		if testCase.framed {
+			d = NewFramedDecompressor()
+		} else {
+			d = NewDecompressor(OrderAscending, 32)
+			d.Forward = true
+			d.Blocked = testCase.blockSize > 0
+		}
+		output, err := d.Decompress(compOutput)
+		assert.Nil(t, err)
+		assert.Equal(t, input, output)
+
+		it, err := d.Iter(compOutput)
+		assert.Nil(t, err)
+		assert.Equal(t, OrderAscending, it.ListOrder)
+		for _, v := range input {
+			got, ok := it.Next()
+			assert.True(t, ok)
+			assert.Equal(t, v, got)
+		}
+		_, ok := it.Next()
+		assert.False(t, ok)
+		assert.Nil(t, it.Err())
+	}
+}
+
+func TestIterator_ForwardStopsEarly(t *testing.T) {
+	input := slice.SortAscUint32Slice(slice.RandomUint32Slice(1000))
+
+	c := NewCompressor(OrderAscending, 32)
+	c.Forward = true
+	compOutput := make([]byte, c.MaxCompressedLen(len(input)))
+	_, err := c.Compress(input, compOutput)
+	assert.Nil(t, err)
+
+	d := NewDecompressor(OrderAscending, 32)
+	d.Forward = true
+	it, err := d.Iter(compOutput)
+	assert.Nil(t, err)
+
+	var output []uint32
+	for v := range it.All() {
+		if v > input[9] {
+			break
+		}
+		output = append(output, v)
+	}
+	assert.Equal(t, input[:10], output)
+}
+
+func TestDecompressor_DecompressForwardCorrupt(t *testing.T) {
+	// one value of width 1 followed by a width growing past 32 bits
+	input := []byte{0x02, 0x00, 0x00, 0x00, 0x00, 0xff, 0xff, 0xff, 0xff, 0xff}
+
+	d := NewDecompressor(OrderAscending, 8)
+	d.Forward = true
+	_, err := d.Decompress(input)
+	assert.Equal(t, ErrCorruptInput, err)
+}
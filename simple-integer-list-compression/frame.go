@@ -0,0 +1,110 @@
+package simple
+
+import "encoding/binary"
+
+// A framed blob starts with a fixed-size header that records everything
+// needed to decode it:
+//
+//	magic (4 bytes) | version (1) | flags (2, LE) | cardinality header size (1) |
+//	payload length in bytes (4, LE) | CRC-32 of the payload (4, LE)
+//
+// The payload that follows is exactly the unframed encoding. Version 1
+// headers had a single byte of flags; they are refused rather than read
+// with this layout.
+const (
+	frameVersion   = 2
+	frameHeaderLen = 16
+)
+
+const (
+	frameFlagDescending = 1 << iota
+	frameFlagBlocked
+	frameFlagDelta
+	frameFlag64
+	frameFlagSigned
+	frameFlagFrameOfReference
+	frameFlagChecksum
+	frameFlagForward
+	frameFlagAdaptive
+	frameFlagHybrid
+	frameFlagRuns
+
+	// knownFrameFlags holds every flag this reader understands. Frames with
+	// any other flag set were written by a newer writer and are refused.
+	knownFrameFlags = frameFlagRuns<<1 - 1
+)
+
+var frameMagic = [4]byte{'S', 'I', 'L', 'C'}
+
+type frameHeader struct {
+	version               byte
+	flags                 uint16
+	cardinalityHeaderSize int
+	payloadLen            int
+	checksum              uint32
+}
+
+func putFrameHeader(output []byte, h frameHeader) {
+	copy(output, frameMagic[:])
+	output[4] = h.version
+	binary.LittleEndian.PutUint16(output[5:], h.flags)
+	output[7] = byte(h.cardinalityHeaderSize)
+	binary.LittleEndian.PutUint32(output[8:], uint32(h.payloadLen))
+	binary.LittleEndian.PutUint32(output[12:], h.checksum)
+}
+
+func parseFrameHeader(input []byte) (frameHeader, error) {
+	var h frameHeader
+
+	if len(input) < len(frameMagic) {
+		return h, ErrInvalidFrame
+	}
+
+	for i, b := range frameMagic {
+		if input[i] != b {
+			return h, ErrInvalidFrame
+		}
+	}
+
+	if len(input) <= len(frameMagic) {
+		return h, ErrTruncatedInput
+	}
+
+	h.version = input[4]
+	if h.version != frameVersion {
+		return h, ErrUnsupportedVersion
+	}
+
+	if len(input) < frameHeaderLen {
+		return h, ErrTruncatedInput
+	}
+
+	h.flags = binary.LittleEndian.Uint16(input[5:])
+	h.cardinalityHeaderSize = int(input[7])
+	h.payloadLen = int(binary.LittleEndian.Uint32(input[8:]))
+	h.checksum = binary.LittleEndian.Uint32(input[12:])
+
+	if h.flags&^knownFrameFlags != 0 {
+		return h, ErrUnsupportedVersion
+	}
+
+	if h.cardinalityHeaderSize < 1 || h.cardinalityHeaderSize > 32 {
+		return h, ErrInvalidFrame
+	}
+
+	return h, nil
+}
+
+func (h frameHeader) listOrder() int {
+	if h.flags&frameFlagDescending != 0 {
+		return OrderDescending
+	}
+	return OrderAscending
+}
+
+func (h frameHeader) wordSize() int {
+	if h.flags&frameFlag64 != 0 {
+		return 64
+	}
+	return 32
+}
@@ -0,0 +1,143 @@
+package simple
+
+import (
+	"encoding/binary"
+	"testing"
+
+	"github.com/vteromero/playground/simple-integer-list-compression/slice"
+	"github.com/stretchr/testify/assert"
+)
+
+const (
+	fuzzDelta = 1 << iota
+	fuzzBlocked
+	fuzzFrameOfReference
+	fuzzFramed
+	fuzzChecksum
+	fuzzForward
+	fuzzAdaptive
+	fuzzHybrid
+	fuzzRuns
+)
+
+func fuzzUint32Slice(data []byte, order int) []uint32 {
+	values := make([]uint32, len(data)/4)
+	for i := range values {
+		values[i] = binary.LittleEndian.Uint32(data[4*i:])
+	}
+	if order == OrderAscending {
+		return slice.SortAscUint32Slice(values)
+	}
+	return slice.SortDescUint32Slice(values)
+}
+
+func fuzzCompressor(order int, cardHeaderSize int, mode uint16) *Compressor {
+	c := NewCompressor(order, cardHeaderSize)
+	c.Delta = mode&fuzzDelta != 0
+	if mode&fuzzBlocked != 0 {
+		c.BlockSize = 1 + int(mode>>15)*DefaultBlockSize
+	}
+	c.FrameOfReference = mode&fuzzFrameOfReference != 0
+	c.Framed = mode&fuzzFramed != 0
+	c.Checksum = mode&fuzzChecksum != 0
+	c.Forward = mode&fuzzForward != 0
+	c.Adaptive = mode&fuzzAdaptive != 0
+	c.Hybrid = mode&fuzzHybrid != 0
+	c.Runs = mode&fuzzRuns != 0
+	return c
+}
+
+func fuzzDecompressor(order int, cardHeaderSize int, mode uint16) *Decompressor {
+	if mode&fuzzFramed != 0 {
+		return NewFramedDecompressor()
+	}
+	d := NewDecompressor(order, cardHeaderSize)
+	d.Delta = mode&fuzzDelta != 0
+	d.Blocked = mode&fuzzBlocked != 0
+	d.FrameOfReference = mode&fuzzFrameOfReference != 0
+	d.Checksum = mode&fuzzChecksum != 0
+	d.Forward = mode&fuzzForward != 0
+	d.Adaptive = mode&fuzzAdaptive != 0
+	d.Hybrid = mode&fuzzHybrid != 0
+	d.Runs = mode&fuzzRuns != 0
+	return d
+}
+
+func FuzzCompressAndDecompress(f *testing.F) {
+	f.Add([]byte{}, byte(OrderAscending), byte(7), uint16(0))
+	f.Add([]byte{0xb8, 0x22, 0x00, 0x00, 0x6f, 0x00, 0x00, 0x00, 0x05, 0x00, 0x00, 0x00}, byte(OrderAscending), byte(7), uint16(0))
+	f.Add([]byte{0xb8, 0x22, 0x00, 0x00, 0x6f, 0x00, 0x00, 0x00, 0x05, 0x00, 0x00, 0x00}, byte(OrderDescending), byte(31), uint16(0xffff))
+	f.Add([]byte{0xff, 0xff, 0xff, 0xff, 0x00, 0x00, 0x00, 0x00}, byte(OrderDescending), byte(1), uint16(fuzzDelta))
+	f.Add([]byte{
+		0xe8, 0x03, 0x00, 0x00, 0xe9, 0x03, 0x00, 0x00, 0xea, 0x03, 0x00, 0x00, 0xeb, 0x03, 0x00, 0x00,
+		0xec, 0x03, 0x00, 0x00, 0xed, 0x03, 0x00, 0x00, 0xee, 0x03, 0x00, 0x00, 0xef, 0x03, 0x00, 0x00,
+	}, byte(OrderAscending), byte(7), uint16(fuzzHybrid))
+	f.Add([]byte{
+		0xe8, 0x03, 0x00, 0x00, 0xe9, 0x03, 0x00, 0x00, 0xea, 0x03, 0x00, 0x00, 0xeb, 0x03, 0x00, 0x00,
+		0x05, 0x00, 0x00, 0x00, 0x06, 0x00, 0x00, 0x00, 0x6f, 0x00, 0x00, 0x00, 0xb8, 0x22, 0x00, 0x00,
+	}, byte(OrderDescending), byte(7), uint16(fuzzHybrid|fuzzRuns))
+
+	f.Fuzz(func(t *testing.T, data []byte, orderByte byte, headerSizeByte byte, mode uint16) {
+		order := int(orderByte % 2)
+		cardHeaderSize := 1 + int(headerSizeByte%32)
+		input := fuzzUint32Slice(data, order)
+
+		c := fuzzCompressor(order, cardHeaderSize, mode)
+		if len(input) >= 1<<uint(cardHeaderSize) {
+			_, err := c.Compress(input, make([]byte, 4*len(input)+64))
+			assert.Equal(t, ErrInputTooLong, err)
+			return
+		}
+
+		compOutput := make([]byte, c.MaxCompressedLen(len(input)))
+		_, err := c.Compress(input, compOutput)
+		if !assert.Nil(t, err) {
+			return
+		}
+
+		d := fuzzDecompressor(order, cardHeaderSize, mode)
+		output, err := d.Decompress(compOutput)
+		assert.Nil(t, err)
+		assert.Equal(t, input, output)
+	})
+}
+
+func FuzzDecompress(f *testing.F) {
+	f.Add([]byte{0x03, 0xb8, 0x22, 0x00, 0x00, 0x6f, 0x40, 0x01}, byte(OrderAscending), byte(7), uint16(0))
+	f.Add([]byte{0x03, 0xb8, 0x22, 0x00, 0x00, 0x6f, 0x40, 0x01}, byte(OrderDescending), byte(7), uint16(fuzzDelta))
+	f.Add([]byte{0x01, 0x80, 0x00, 0x00}, byte(OrderDescending), byte(7), uint16(fuzzBlocked))
+	f.Add([]byte{
+		'S', 'I', 'L', 'C', 0x02, 0x01, 0x00, 0x08, 0x08, 0x00, 0x00, 0x00, 0xfe, 0x8e, 0xa6, 0x76,
+		0x03, 0xb8, 0x22, 0x00, 0x00, 0x6f, 0x40, 0x01,
+	}, byte(OrderDescending), byte(7), uint16(fuzzFramed))
+
+	f.Fuzz(func(t *testing.T, input []byte, orderByte byte, headerSizeByte byte, mode uint16) {
+		order := int(orderByte % 2)
+		cardHeaderSize := 1 + int(headerSizeByte%32)
+
+		d := fuzzDecompressor(order, cardHeaderSize, mode)
+		output, err := d.Decompress(input)
+		if err != nil {
+			assert.Nil(t, output)
+			return
+		}
+
+		// every value takes at least one bit of the input, unless it is
+		// part of a run, which framed input can turn on whatever the mode
+		if !d.Runs {
+			assert.LessOrEqual(t, len(output), 8*len(input))
+		}
+
+		if d.Blocked {
+			l, err := d.Open(input)
+			if !assert.Nil(t, err) {
+				return
+			}
+			for i, v := range output {
+				got, err := l.Get(i)
+				assert.Nil(t, err)
+				assert.Equal(t, v, got)
+			}
+		}
+	})
+}
@@ -0,0 +1,334 @@
+package simple
+
+import (
+	"math/bits"
+)
+
+// In hybrid mode every list, or every block of a blocked list, is preceded
+// by a container code telling how its values follow: in the usual encoding
+// (the array container), as a bitmap, or as runs (see runs.go), whichever
+// takes fewer bits among the enabled ones. The code is a 0 bit for arrays
+// and a 1 bit for the other container, or 10 for bitmaps and 11 for runs
+// when both are enabled. Bitmaps and runs can only hold strictly sorted
+// lists, and empty lists have no container code.
+//
+// A bitmap holds the first stored value and the span between the smallest
+// and largest values, both at full width, followed by span+1 bits where bit
+// i is set when the value i away from the first one is in the list. Bits
+// are laid out in the same direction as the values would be stored, so
+// that all containers decode in the same order.
+const (
+	containerArray = iota
+	containerBitmap
+	containerRuns
+)
+
+// storedAscending reports whether values are stored in ascending order,
+// which is the case for ascending lists unless their width chain is written
+// back-to-front.
+func (c *GenericCompressor[T]) storedAscending() bool {
+	return c.ListOrder == OrderAscending && (c.Delta || c.Forward || c.Adaptive)
+}
+
+func (d *GenericDecompressor[T]) storedAscending() bool {
+	return d.ListOrder == OrderAscending && (d.Delta || d.Forward || d.Adaptive)
+}
+
+func isStrictlySorted[T Unsigned](values []T, order int) bool {
+	for i := 1; i < len(values); i++ {
+		if order == OrderAscending && values[i] <= values[i-1] ||
+			order == OrderDescending && values[i] >= values[i-1] {
+			return false
+		}
+	}
+	return true
+}
+
+// bitmapSpan returns the span between the smallest and largest values, and
+// false if values cannot be stored as a bitmap.
+func bitmapSpan[T Unsigned](values []T, order int) (uint64, bool) {
+	if len(values) == 0 || !isStrictlySorted(values, order) {
+		return 0, false
+	}
+	if order == OrderAscending {
+		return uint64(values[len(values)-1] - values[0]), true
+	}
+	return uint64(values[0] - values[len(values)-1]), true
+}
+
+func (c *GenericCompressor[T]) hasContainers() bool {
+	return c.Hybrid || c.Runs
+}
+
+func (d *GenericDecompressor[T]) hasContainers() bool {
+	return d.Hybrid || d.Runs
+}
+
+func (c *GenericCompressor[T]) containerCodeLen(container int) int {
+	if container != containerArray && c.Hybrid && c.Runs {
+		return 2
+	}
+	return 1
+}
+
+func bitmapBitsLen[T Unsigned](span uint64) int {
+	return 2*wordSize[T]() + int(span) + 1
+}
+
+// container returns the container that stores values in the fewest bits,
+// along with those bits, container code included.
+func (c *GenericCompressor[T]) container(values []T) (int, int) {
+	container, bits := containerArray, c.arrayBitsLen(values)
+
+	span, ok := bitmapSpan(values, c.ListOrder)
+	if c.Hybrid && ok && span < uint64(bits) && bitmapBitsLen[T](span) < bits {
+		container, bits = containerBitmap, bitmapBitsLen[T](span)
+	}
+	if c.Runs && ok {
+		if runBits := c.runsBitsLen(values); runBits < bits {
+			container, bits = containerRuns, runBits
+		}
+	}
+
+	return container, c.containerCodeLen(container) + bits
+}
+
+func (c *GenericCompressor[T]) containerBitsLen(values []T) int {
+	if len(values) == 0 {
+		return 0
+	}
+	_, bits := c.container(values)
+	return bits
+}
+
+func (c *GenericCompressor[T]) writeContainer(values []T) error {
+	if len(values) == 0 {
+		return nil
+	}
+
+	container, _ := c.container(values)
+
+	code := uint64(0)
+	switch container {
+	case containerBitmap:
+		code = 1
+	case containerRuns:
+		code = 1
+		if c.Hybrid {
+			code = 3
+		}
+	}
+	if err := c.writer.Write(code, c.containerCodeLen(container)); err != nil {
+		return err
+	}
+
+	switch container {
+	case containerBitmap:
+		return c.writeBitmap(values)
+	case containerRuns:
+		return c.writeRuns(values)
+	}
+	return c.encodeArray(values)
+}
+
+// storedValue returns the i-th value in the order values are stored, minus
+// the base.
+func (c *GenericCompressor[T]) storedValue(values []T, i int) T {
+	if c.ListOrder == OrderAscending && !c.storedAscending() {
+		return values[len(values)-1-i] - c.base
+	}
+	return values[i] - c.base
+}
+
+// distance returns how far v is from first in the order values are stored.
+func (c *GenericCompressor[T]) distance(first, v T) uint64 {
+	if c.storedAscending() {
+		return uint64(v - first)
+	}
+	return uint64(first - v)
+}
+
+func (c *GenericCompressor[T]) writeZeros(n uint64) error {
+	for n > 0 {
+		k := min(n, 64)
+		if err := c.writer.Write(0, int(k)); err != nil {
+			return err
+		}
+		n -= k
+	}
+	return nil
+}
+
+func (c *GenericCompressor[T]) writeBitmap(values []T) error {
+	n := len(values)
+	first := c.storedValue(values, 0)
+
+	if err := c.writer.Write(uint64(first), wordSize[T]()); err != nil {
+		return err
+	}
+	if err := c.writer.Write(c.distance(first, c.storedValue(values, n-1)), wordSize[T]()); err != nil {
+		return err
+	}
+
+	pos := uint64(0)
+	for i := range values {
+		p := c.distance(first, c.storedValue(values, i))
+		if err := c.writeZeros(p - pos); err != nil {
+			return err
+		}
+		if err := c.writer.Write(1, 1); err != nil {
+			return err
+		}
+		pos = p + 1
+	}
+
+	return nil
+}
+
+// bitmapReader walks the set bits of a bitmap, reading it up to 64 bits at
+// a time. pos is the position of the first bit held in word.
+type bitmapReader[T Unsigned] struct {
+	first     T
+	ascending bool
+	span      uint64
+	pos       uint64
+	word      uint64
+	bits      int
+}
+
+func (d *GenericDecompressor[T]) readBitmapHeader() (bitmapReader[T], error) {
+	b := bitmapReader[T]{ascending: d.storedAscending()}
+
+	first, err := d.read(wordSize[T]())
+	if err != nil {
+		return b, err
+	}
+	span, err := d.read(wordSize[T]())
+	if err != nil {
+		return b, err
+	}
+	if span >= uint64(d.remainingBits()) {
+		return b, ErrTruncatedInput
+	}
+
+	b.first = T(first)
+	b.span = span
+	if b.ascending && span > uint64(^T(0)-b.first) || !b.ascending && span > uint64(b.first) {
+		return b, ErrCorruptInput
+	}
+
+	return b, nil
+}
+
+func (d *GenericDecompressor[T]) readBitmapValue(b *bitmapReader[T]) (T, error) {
+	for {
+		if b.bits == 0 {
+			if b.pos > b.span {
+				return 0, ErrCorruptInput
+			}
+			n := int(min(b.span+1-b.pos, 64))
+			word, err := d.read(n)
+			if err != nil {
+				return 0, err
+			}
+			b.word, b.bits = word, n
+		}
+
+		if b.word == 0 {
+			b.pos += uint64(b.bits)
+			b.bits = 0
+			continue
+		}
+
+		tz := bits.TrailingZeros64(b.word)
+		p := b.pos + uint64(tz)
+		b.word >>= tz + 1
+		b.bits -= tz + 1
+		b.pos = p + 1
+
+		if b.ascending {
+			return b.first + T(p), nil
+		}
+		return b.first - T(p), nil
+	}
+}
+
+// end checks that the last value read was the last bit of the
+// bitmap.
+func (b *bitmapReader[T]) end() error {
+	if b.bits != 0 || b.pos != b.span+1 {
+		return ErrCorruptInput
+	}
+	return nil
+}
+
+// setStored sets the i-th value, in the order values are stored, of output.
+func (d *GenericDecompressor[T]) setStored(output []T, i int, v T) {
+	if d.ListOrder == OrderAscending && !d.storedAscending() {
+		output[len(output)-1-i] = v
+	} else {
+		output[i] = v
+	}
+}
+
+func (d *GenericDecompressor[T]) readValuesBitmap(output []T) ([]T, error) {
+	b, err := d.readBitmapHeader()
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range output {
+		v, err := d.readBitmapValue(&b)
+		if err != nil {
+			return nil, err
+		}
+		d.setStored(output, i, v)
+	}
+
+	if err := b.end(); err != nil {
+		return nil, err
+	}
+	return output, nil
+}
+
+func (d *GenericDecompressor[T]) readContainerCode() (int, error) {
+	bit, err := d.read(1)
+	if err != nil || bit == 0 {
+		return containerArray, err
+	}
+
+	if !d.Runs {
+		return containerBitmap, nil
+	}
+	if !d.Hybrid {
+		return containerRuns, nil
+	}
+
+	bit, err = d.read(1)
+	if err != nil {
+		return 0, err
+	}
+	if bit == 0 {
+		return containerBitmap, nil
+	}
+	return containerRuns, nil
+}
+
+func (d *GenericDecompressor[T]) readContainer(output []T) ([]T, error) {
+	if len(output) == 0 {
+		return output, nil
+	}
+
+	container, err := d.readContainerCode()
+	if err != nil {
+		return nil, err
+	}
+
+	switch container {
+	case containerBitmap:
+		return d.readValuesBitmap(output)
+	case containerRuns:
+		return d.readValuesRuns(output)
+	}
+	return d.decodeArray(output)
+}
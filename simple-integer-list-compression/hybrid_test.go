@@ -0,0 +1,232 @@
+package simple
+
+import (
+	"math/rand"
+	"slices"
+	"testing"
+
+	"github.com/vteromero/playground/simple-integer-list-compression/slice"
+	"github.com/stretchr/testify/assert"
+)
+
+func rangeUint32Slice(start uint32, n int) []uint32 {
+	s := make([]uint32, n)
+	for i := range s {
+		s[i] = start + uint32(i)
+	}
+	return s
+}
+
+func TestCompressor_CompressHybrid(t *testing.T) {
+	params := []struct {
+		order     int
+		input     []uint32
+		expectedN int
+	}{
+		{OrderAscending, []uint32{}, 8},
+		{OrderAscending, []uint32{5, 111, 8888}, 8 + 1 + 53},
+		{OrderAscending, rangeUint32Slice(1000, 100), 8 + 1 + 64 + 100},
+		{OrderAscending, []uint32{1000, 1002, 1004, 1006, 1008, 1010, 1012, 1014}, 8 + 1 + 64 + 15},
+		{OrderAscending, []uint32{7, 7, 7, 7, 7, 7, 7, 7, 7, 7, 7, 7, 7, 7, 7, 7, 7, 7, 7, 7, 7, 7}, 8 + 1 + 32 + 21*3},
+		{OrderDescending, slice.SortDescUint32Slice(rangeUint32Slice(1000, 100)), 8 + 1 + 64 + 100},
+	}
+
+	for _, testCase := range params {
+		c := NewCompressor(testCase.order, 8)
+		c.Hybrid = true
+		output := make([]byte, c.MaxCompressedLen(len(testCase.input)))
+		m, err := c.Compress(testCase.input, output)
+		assert.Nil(t, err)
+		assert.Equal(t, testCase.expectedN, m)
+
+		d := NewDecompressor(testCase.order, 8)
+		d.Hybrid = true
+		decompressed, err := d.Decompress(output)
+		assert.Nil(t, err)
+		assert.Equal(t, testCase.input, decompressed)
+	}
+}
+
+func TestCompressAndDecompressHybrid(t *testing.T) {
+	params := []struct {
+		order     int
+		inputSize int
+		density   float64
+		blockSize int
+		delta     bool
+		forward   bool
+		adaptive  bool
+		framed    bool
+	}{
+		{OrderAscending, 0, 1, 0, false, false, false, false},
+		{OrderAscending, 1, 1, 0, false, false, false, false},
+		{OrderAscending, 1000, 0.5, 0, false, false, false, false},
+		{OrderAscending, 1000, 0.5, 0, true, false, false, false},
+		{OrderAscending, 1000, 0.5, 0, false, true, false, false},
+		{OrderAscending, 1000, 0.5, 0, false, false, true, false},
+		{OrderAscending, 1000, 0.5, 0, false, false, false, true},
+		{OrderAscending, 1000, 0.001, 0, false, false, false, false},
+		{OrderAscending, 1000, 0.1, DefaultBlockSize, false, false, false, false},
+		{OrderAscending, 1000, 0.1, DefaultBlockSize, true, false, false, true},
+		{OrderAscending, 1000, 1, 1, false, false, false, false},
+		{OrderDescending, 1, 1, 0, false, false, false, false},
+		{OrderDescending, 1000, 0.5, 0, false, false, false, false},
+		{OrderDescending, 1000, 0.5, 0, true, false, false, false},
+		{OrderDescending, 1000, 0.5, 0, false, false, true, false},
+		{OrderDescending, 1000, 0.001, 0, false, false, false, true},
+		{OrderDescending, 1000, 0.1, DefaultBlockSize, false, false, false, false},
+	}
+
+	r := rand.New(rand.NewSource(1))
+	for _, testCase := range params {
+		universe := uint64(float64(testCase.inputSize) / testCase.density)
+		input := slice.RandomSortedSet(r, testCase.inputSize, universe)
+		if testCase.order == OrderDescending {
+			input = slice.SortDescUint32Slice(input)
+		}
+
+		c := NewCompressor(testCase.order, 32)
+		c.Hybrid = true
+		c.BlockSize = testCase.blockSize
+		c.Delta = testCase.delta
+		c.Forward = testCase.forward
+		c.Adaptive = testCase.adaptive
+		c.Framed = testCase.framed
+		compOutput := make([]byte, c.MaxCompressedLen(testCase.inputSize))
+		_, err := c.Compress(input, compOutput)
+		assert.Nil(t, err)
+
+		var d *Decompressor
+		if testCase.framed {
+			d = NewFramedDecompressor()
+		} else {
+			d = NewDecompressor(testCase.order, 32)
+			d.Hybrid = true
+			d.Blocked = testCase.blockSize > 0
+			d.Delta = testCase.delta
+			d.Forward = testCase.forward
+			d.Adaptive = testCase.adaptive
+		}
+		output, err := d.Decompress(compOutput)
+		assert.Nil(t, err)
+		assert.Equal(t, input, output)
+
+		it, err := d.Iter(compOutput)
+		assert.Nil(t, err)
+		expected := slices.Clone(input)
+		if it.ListOrder != testCase.order {
+			slices.Reverse(expected)
+		}
+		iterOutput := slices.Collect(it.All())
+		assert.Nil(t, it.Err())
+		assert.Equal(t, len(expected), len(iterOutput))
+		if len(expected) > 0 {
+			assert.Equal(t, expected, iterOutput)
+		}
+	}
+}
+
+func TestCompressAndDecompressHybrid64(t *testing.T) {
+	input := []uint64{1 << 40, 1<<40 + 1, 1<<40 + 2, 1<<40 + 4, 1<<40 + 5, 1<<40 + 7}
+
+	c := NewCompressor64(OrderAscending, 32)
+	c.Hybrid = true
+	c.Framed = true
+	compOutput := make([]byte, c.MaxCompressedLen(len(input)))
+	_, err := c.Compress(input, compOutput)
+	assert.Nil(t, err)
+
+	output, err := NewFramedDecompressor64().Decompress(compOutput)
+	assert.Nil(t, err)
+	assert.Equal(t, input, output)
+}
+
+func TestCompressHybridIsSmallerOnDenseInput(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+
+	// dense blocks alternate with sparse ones
+	var input []uint32
+	for i := 0; i < 8; i++ {
+		universe := uint64(DefaultBlockSize * 2)
+		if i%2 == 1 {
+			universe = 1 << 24
+		}
+		block := slice.RandomSortedSet(r, DefaultBlockSize, universe)
+		start := uint32(i) << 25
+		for _, v := range block {
+			input = append(input, start+v)
+		}
+	}
+
+	plain := NewCompressor(OrderAscending, 32)
+	plain.BlockSize = DefaultBlockSize
+	plainN, err := plain.Compress(input, make([]byte, plain.MaxCompressedLen(len(input))))
+	assert.Nil(t, err)
+
+	c := NewCompressor(OrderAscending, 32)
+	c.BlockSize = DefaultBlockSize
+	c.Hybrid = true
+	compOutput := make([]byte, c.MaxCompressedLen(len(input)))
+	hybridN, err := c.Compress(input, compOutput)
+	assert.Nil(t, err)
+	assert.Less(t, hybridN, plainN)
+
+	d := NewDecompressor(OrderAscending, 32)
+	d.Blocked = true
+	d.Hybrid = true
+	l, err := d.Open(compOutput)
+	assert.Nil(t, err)
+	for i, v := range input {
+		got, err := l.Get(i)
+		assert.Nil(t, err)
+		assert.Equal(t, v, got)
+	}
+}
+
+func TestDecompressor_DecompressHybridCorrupt(t *testing.T) {
+	input := rangeUint32Slice(1000, 100)
+
+	c := NewCompressor(OrderAscending, 8)
+	c.Hybrid = true
+	compOutput := make([]byte, c.MaxCompressedLen(len(input)))
+	n, err := c.Compress(input, compOutput)
+	assert.Nil(t, err)
+	compOutput = compOutput[:sizeInBytes(n)]
+
+	// the bitmap starts after the cardinality, the container bit and the
+	// bitmap header
+	bitmapOffset := 8 + 1 + 64
+
+	missingValue := slices.Clone(compOutput)
+	missingValue[(bitmapOffset+50)/8] &^= 1 << ((bitmapOffset + 50) % 8)
+
+	missingLast := slices.Clone(compOutput)
+	missingLast[(bitmapOffset+99)/8] &^= 1 << ((bitmapOffset + 99) % 8)
+
+	params := []struct {
+		input       []byte
+		expectedErr error
+	}{
+		{compOutput, nil},
+		{missingValue, ErrCorruptInput},
+		{missingLast, ErrCorruptInput},
+		{compOutput[:len(compOutput)-1], ErrTruncatedInput},
+		{compOutput[:5], ErrTruncatedInput},
+	}
+
+	for _, testCase := range params {
+		d := NewDecompressor(OrderAscending, 8)
+		d.Hybrid = true
+		_, err := d.Decompress(testCase.input)
+		assert.Equal(t, testCase.expectedErr, err)
+
+		it, err := d.Iter(testCase.input)
+		if err != nil {
+			assert.Equal(t, testCase.expectedErr, err)
+			continue
+		}
+		for range it.All() {
+		}
+		assert.Equal(t, testCase.expectedErr, it.Err())
+	}
+}
@@ -0,0 +1,221 @@
+package simple
+
+import (
+	"iter"
+)
+
+// GenericIterator decodes a list one value at a time. Values come out in
+// the order they are stored, which is the list order except for ascending
+// lists encoded without Delta, Forward or Adaptive: their width chain is
+// written back-to-front, so they are yielded in descending order. ListOrder
+// tells which one applies.
+type GenericIterator[T Unsigned] struct {
+	ListOrder int
+	decoder   GenericDecompressor[T]
+	blocks    *GenericBlockList[T]
+	reversed  bool
+	index     int
+	width     int
+	prev      T
+	bitmap    *bitmapReader[T]
+	runs      *runReader[T]
+	err       error
+}
+
+type Iterator = GenericIterator[uint32]
+
+type Iterator64 = GenericIterator[uint64]
+
+// Iter returns an iterator over the values encoded in input. Header errors
+// are reported right away; errors found while decoding stop the iteration
+// and are reported by Err.
+func (d *GenericDecompressor[T]) Iter(input []byte) (*GenericIterator[T], error) {
+	if err := d.begin(input); err != nil {
+		return nil, err
+	}
+
+	it := &GenericIterator[T]{
+		ListOrder: d.ListOrder,
+		reversed:  d.ListOrder == OrderAscending && !d.storedAscending(),
+		width:     wordSize[T](),
+	}
+	if it.reversed {
+		it.ListOrder = OrderDescending
+	}
+
+	if d.Blocked {
+		l, err := d.Open(input)
+		if err != nil {
+			return nil, err
+		}
+		it.blocks = l
+		if it.reversed {
+			it.index = l.Len() - 1
+		}
+	} else if d.Checksum && d.cardinality == 0 {
+		if err := verifyChecksum(d.input, d.offset); err != nil {
+			return nil, err
+		}
+	}
+
+	it.decoder = *d
+
+	return it, nil
+}
+
+func (it *GenericIterator[T]) nextBlocked() (T, bool) {
+	if it.index < 0 || it.index >= it.blocks.Len() {
+		return 0, false
+	}
+
+	v, err := it.blocks.Get(it.index)
+	if err != nil {
+		it.err = err
+		return 0, false
+	}
+
+	if it.reversed {
+		it.index--
+	} else {
+		it.index++
+	}
+	return v, true
+}
+
+// readContainer reads the container code of the list, along with the
+// bitmap header when it is stored as a bitmap.
+func (it *GenericIterator[T]) readContainer() error {
+	d := &it.decoder
+
+	container, err := d.readContainerCode()
+	if err != nil {
+		return err
+	}
+
+	switch container {
+	case containerBitmap:
+		b, err := d.readBitmapHeader()
+		if err != nil {
+			return err
+		}
+		it.bitmap = &b
+	case containerRuns:
+		it.runs = &runReader[T]{ascending: d.storedAscending(), remaining: d.cardinality}
+	}
+	return nil
+}
+
+func (it *GenericIterator[T]) nextValue() (T, error) {
+	d := &it.decoder
+
+	if d.hasContainers() && it.index == 0 {
+		if err := it.readContainer(); err != nil {
+			return 0, err
+		}
+	}
+
+	if it.runs != nil {
+		return d.readRunValue(it.runs)
+	}
+
+	if it.bitmap != nil {
+		v, err := d.readBitmapValue(it.bitmap)
+		if err != nil {
+			return 0, err
+		}
+		if it.index == d.cardinality-1 {
+			if err := it.bitmap.end(); err != nil {
+				return 0, err
+			}
+		}
+		return v, nil
+	}
+
+	if d.Delta && it.index > 0 {
+		g, err := d.readGap(it.width)
+		if err != nil {
+			return 0, err
+		}
+		it.prev = applyGap(it.prev, g, d.ListOrder)
+		it.width = bitsLen(g)
+		return it.prev, nil
+	}
+
+	if d.isAdaptive() {
+		v, w, err := d.readAdaptive(it.width)
+		if err != nil {
+			return 0, err
+		}
+		it.width = w
+		return v, nil
+	}
+
+	if d.isForward() && it.index > 0 {
+		w, err := d.readForwardWidth(it.width)
+		if err != nil {
+			return 0, err
+		}
+		v, err := d.read(w)
+		if err != nil {
+			return 0, err
+		}
+		it.width = w
+		return T(v), nil
+	}
+
+	v, err := d.read(it.width)
+	if err != nil {
+		return 0, err
+	}
+	it.prev = T(v)
+	it.width = bitsLen(it.prev)
+	return it.prev, nil
+}
+
+// Next returns the next value and true, or false when the list is exhausted
+// or an error occurred.
+func (it *GenericIterator[T]) Next() (T, bool) {
+	if it.err != nil {
+		return 0, false
+	}
+
+	if it.blocks != nil {
+		return it.nextBlocked()
+	}
+
+	d := &it.decoder
+	if it.index >= d.cardinality {
+		return 0, false
+	}
+
+	v, err := it.nextValue()
+	if err != nil {
+		it.err = err
+		return 0, false
+	}
+	it.index++
+
+	if it.index == d.cardinality && d.Checksum {
+		if err := verifyChecksum(d.input, d.offset); err != nil {
+			it.err = err
+			return 0, false
+		}
+	}
+
+	return v + d.base, true
+}
+
+func (it *GenericIterator[T]) Err() error {
+	return it.err
+}
+
+// All returns an iter.Seq over the remaining values.
+func (it *GenericIterator[T]) All() iter.Seq[T] {
+	return func(yield func(T) bool) {
+		for v, ok := it.Next(); ok; v, ok = it.Next() {
+			if !yield(v) {
+				return
+			}
+		}
+	}
+}
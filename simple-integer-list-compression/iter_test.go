@@ -0,0 +1,137 @@
+package simple
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/vteromero/playground/simple-integer-list-compression/slice"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDecompressor_Iter(t *testing.T) {
+	input := []byte{0x03, 0xb8, 0x22, 0x00, 0x00, 0x6f, 0x40, 0x01}
+
+	it, err := NewDecompressor(OrderAscending, 8).Iter(input)
+	assert.Nil(t, err)
+	assert.Equal(t, OrderDescending, it.ListOrder)
+
+	var output []uint32
+	for v, ok := it.Next(); ok; v, ok = it.Next() {
+		output = append(output, v)
+	}
+	assert.Nil(t, it.Err())
+	assert.Equal(t, []uint32{8888, 111, 5}, output)
+}
+
+func TestCompressAndIter(t *testing.T) {
+	params := []struct {
+		order            int
+		inputSize        int
+		blockSize        int
+		delta            bool
+		frameOfReference bool
+		checksum         bool
+		framed           bool
+	}{
+		{OrderAscending, 0, 0, false, false, false, false},
+		{OrderAscending, 1000, 0, false, false, false, false},
+		{OrderAscending, 1000, 0, true, false, false, false},
+		{OrderAscending, 1000, 0, false, true, true, false},
+		{OrderAscending, 1000, 100, false, false, false, false},
+		{OrderAscending, 1000, 100, true, true, true, true},
+		{OrderAscending, 0, 0, false, false, true, true},
+		{OrderDescending, 0, 0, false, false, false, false},
+		{OrderDescending, 1000, 0, false, false, false, false},
+		{OrderDescending, 1000, 0, true, true, false, false},
+		{OrderDescending, 1000, 100, false, false, true, false},
+		{OrderDescending, 1000, 100, true, false, false, true},
+	}
+
+	for _, testCase := range params {
+		var input []uint32
+		if testCase.order == OrderAscending {
+			input = slice.SortAscUint32Slice(slice.RandomUint32Slice(testCase.inputSize))
+		} else {
+			input = slice.SortDescUint32Slice(slice.RandomUint32Slice(testCase.inputSize))
+		}
+
+		c := NewCompressor(testCase.order, 32)
+		c.BlockSize = testCase.blockSize
+		c.Delta = testCase.delta
+		c.FrameOfReference = testCase.frameOfReference
+		c.Checksum = testCase.checksum
+		c.Framed = testCase.framed
+		compOutput := make([]byte, c.MaxCompressedLen(testCase.inputSize))
+		_, err := c.Compress(input, compOutput)
+		assert.Nil(t, err)
+
+		var d *Decompressor
+		if testCase.framed {
+			d = NewFramedDecompressor()
+		} else {
+			d = NewDecompressor(testCase.order, 32)
+			d.Blocked = testCase.blockSize > 0
+			d.Delta = testCase.delta
+			d.FrameOfReference = testCase.frameOfReference
+			d.Checksum = testCase.checksum
+		}
+
+		it, err := d.Iter(compOutput)
+		assert.Nil(t, err)
+
+		expected := slices.Clone(input)
+		if testCase.order == OrderAscending && !testCase.delta {
+			slices.Reverse(expected)
+			assert.Equal(t, OrderDescending, it.ListOrder)
+		} else {
+			assert.Equal(t, testCase.order, it.ListOrder)
+		}
+
+		output := slices.Collect(it.All())
+		assert.Nil(t, it.Err())
+		assert.Equal(t, len(expected), len(output))
+		if len(expected) > 0 {
+			assert.Equal(t, expected, output)
+		}
+	}
+}
+
+func TestIterator_AllStopsEarly(t *testing.T) {
+	input := []byte{0x03, 0xb8, 0x22, 0x00, 0x00, 0x6f, 0x40, 0x01}
+	it, err := NewDecompressor(OrderDescending, 8).Iter(input)
+	assert.Nil(t, err)
+
+	for v := range it.All() {
+		assert.Equal(t, uint32(8888), v)
+		break
+	}
+
+	v, ok := it.Next()
+	assert.True(t, ok)
+	assert.Equal(t, uint32(111), v)
+}
+
+func TestIterator_Err(t *testing.T) {
+	params := []struct {
+		decompressor *Decompressor
+		input        []byte
+		expectedErr  error
+		iterErr      error
+	}{
+		{NewDecompressor(OrderDescending, 0), []byte{0x00}, ErrCardinalityHeaderSizeOutOfBound, nil},
+		{NewDecompressor(OrderDescending, 8), []byte{}, ErrTruncatedInput, nil},
+		{NewDecompressor(OrderDescending, 8), []byte{0x03, 0xb8, 0x22, 0x00, 0x00, 0x6f}, nil, ErrTruncatedInput},
+	}
+
+	for _, testCase := range params {
+		it, err := testCase.decompressor.Iter(testCase.input)
+		assert.Equal(t, testCase.expectedErr, err)
+		if err != nil {
+			continue
+		}
+
+		for range it.All() {
+		}
+		assert.Equal(t, testCase.iterErr, it.Err())
+	}
+}
@@ -0,0 +1,32 @@
+package simple
+
+// In frame-of-reference mode the minimum value of the list is written at
+// full width right after the cardinality, and the values are encoded as
+// their difference to it. The compressor subtracts this base as it writes
+// values, so the input is never copied.
+func minValue[T Unsigned](values []T) T {
+	var m T
+	for i, v := range values {
+		if i == 0 || v < m {
+			m = v
+		}
+	}
+	return m
+}
+
+func (c *GenericCompressor[T]) hasBase() bool {
+	return c.FrameOfReference || c.signed
+}
+
+func (d *GenericDecompressor[T]) hasBase() bool {
+	return d.FrameOfReference || d.signed
+}
+
+func (d *GenericDecompressor[T]) addBase(values []T) {
+	if d.base == 0 {
+		return
+	}
+	for i := range values {
+		values[i] += d.base
+	}
+}
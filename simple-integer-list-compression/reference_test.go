@@ -0,0 +1,135 @@
+package simple
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/vteromero/playground/simple-integer-list-compression/slice"
+	"github.com/stretchr/testify/assert"
+)
+
+func randomRangeUint32Slice(n int, offset, size uint32) []uint32 {
+	s := make([]uint32, n)
+	for i := range s {
+		s[i] = offset + uint32(rand.Int63n(int64(size)))
+	}
+	return s
+}
+
+func TestCompressor_CompressFrameOfReference(t *testing.T) {
+	params := []struct {
+		order          int
+		input          []uint32
+		expectedN      int
+		expectedOutput []byte
+	}{
+		{OrderAscending, []uint32{}, 40, []byte{0x00, 0x00, 0x00, 0x00, 0x00}},
+		{OrderDescending, []uint32{8888, 111, 5}, 8 + 32 + 32 + 14 + 7, []byte{0x03, 0x05, 0x00, 0x00, 0x00, 0xb3, 0x22, 0x00, 0x00, 0x6a, 0x00, 0x00}},
+		{OrderAscending, []uint32{5, 111, 8888}, 8 + 32 + 32 + 14 + 7, []byte{0x03, 0x05, 0x00, 0x00, 0x00, 0xb3, 0x22, 0x00, 0x00, 0x6a, 0x00, 0x00}},
+	}
+
+	for _, testCase := range params {
+		c := NewCompressor(testCase.order, 8)
+		c.FrameOfReference = true
+		output := make([]byte, c.MaxCompressedLen(len(testCase.input)))
+		m, err := c.Compress(testCase.input, output)
+		assert.Nil(t, err)
+		assert.Equal(t, testCase.expectedN, m)
+		assert.Equal(t, testCase.expectedOutput, output[:sizeInBytes(m)])
+	}
+}
+
+func TestCompressAndDecompressFrameOfReference(t *testing.T) {
+	params := []struct {
+		order     int
+		inputSize int
+		delta     bool
+		blockSize int
+		framed    bool
+	}{
+		{OrderAscending, 0, false, 0, false},
+		{OrderAscending, 1, false, 0, false},
+		{OrderAscending, 1000, false, 0, false},
+		{OrderAscending, 1000, true, 0, false},
+		{OrderAscending, 1000, false, DefaultBlockSize, false},
+		{OrderAscending, 1000, true, DefaultBlockSize, true},
+		{OrderDescending, 0, false, 0, false},
+		{OrderDescending, 1, false, 0, false},
+		{OrderDescending, 1000, false, 0, false},
+		{OrderDescending, 1000, true, 0, false},
+		{OrderDescending, 1000, false, DefaultBlockSize, false},
+		{OrderDescending, 1000, true, DefaultBlockSize, true},
+	}
+
+	for _, testCase := range params {
+		input := randomRangeUint32Slice(testCase.inputSize, 3000000000, 100000)
+		if testCase.order == OrderAscending {
+			input = slice.SortAscUint32Slice(input)
+		} else {
+			input = slice.SortDescUint32Slice(input)
+		}
+
+		c := NewCompressor(testCase.order, 32)
+		c.FrameOfReference = true
+		c.Delta = testCase.delta
+		c.BlockSize = testCase.blockSize
+		c.Framed = testCase.framed
+		compOutput := make([]byte, c.MaxCompressedLen(len(input)))
+		_, err := c.Compress(input, compOutput)
+		assert.Nil(t, err)
+
+		var d *Decompressor
+		if testCase.framed {
+			d = NewFramedDecompressor()
+		} else {
+			d = NewDecompressor(testCase.order, 32)
+			d.FrameOfReference = true
+			d.Delta = testCase.delta
+			d.Blocked = testCase.blockSize > 0
+		}
+		output, err := d.Decompress(compOutput)
+		assert.Nil(t, err)
+		assert.Equal(t, input, output)
+
+		if testCase.blockSize > 0 && len(input) > 0 {
+			l, err := d.Open(compOutput)
+			assert.Nil(t, err)
+
+			i, err := l.Seek(input[500])
+			assert.Nil(t, err)
+			v, err := l.Get(i)
+			assert.Nil(t, err)
+			assert.Equal(t, input[500], v)
+		}
+	}
+}
+
+func TestCompressFrameOfReferenceIsSmallerOnOffsetInput(t *testing.T) {
+	input := slice.SortAscUint32Slice(randomRangeUint32Slice(10000, 3000000000, 100000))
+
+	c := NewCompressor(OrderAscending, 32)
+	n, err := c.Compress(input, make([]byte, c.MaxCompressedLen(len(input))))
+	assert.Nil(t, err)
+
+	c.FrameOfReference = true
+	forN, err := c.Compress(input, make([]byte, c.MaxCompressedLen(len(input))))
+	assert.Nil(t, err)
+
+	assert.Less(t, forN, n*2/3)
+}
+
+func TestCompressor_CompressFrameOfReferenceDoesNotCopy(t *testing.T) {
+	input := slice.SortAscUint32Slice(randomRangeUint32Slice(10000, 3000000000, 100000))
+
+	c := NewCompressor(OrderAscending, 32)
+	output := make([]byte, c.MaxCompressedLen(len(input)))
+	plain := testing.AllocsPerRun(10, func() {
+		c.Compress(input, output)
+	})
+
+	c.FrameOfReference = true
+	allocs := testing.AllocsPerRun(10, func() {
+		c.Compress(input, output)
+	})
+	assert.Equal(t, plain, allocs)
+}
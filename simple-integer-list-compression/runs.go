@@ -0,0 +1,170 @@
+package simple
+
+// A run container stores a strictly sorted list as runs of consecutive
+// values, in the order values are stored. The first run starts with its
+// first value at full width, and every following run with its distance to
+// the last value of the previous run, minus one. Each run then has its
+// length minus one. Distances and lengths are written as their width
+// (minus one) in deltaWidthHeaderSize bits followed by the number at that
+// width. The runs end once they add up to the cardinality.
+//
+// As a run takes a few bits whatever its length, the input size does not
+// bound the cardinality of lists with runs. Those holding more values than
+// the input has bits are bounded by MaxCardinality instead, or by
+// DefaultMaxCardinality when it is not set. The compressor refuses lists
+// that a decompressor with the same MaxCardinality would refuse, so longer
+// lists take setting MaxCardinality on both.
+const DefaultMaxCardinality = 1 << 16
+
+func sizedBitsLen[T Unsigned](x T) int {
+	return deltaWidthHeaderSize[T]() + bitsLen(x)
+}
+
+// runs calls f with the first and last values, in the order values are
+// stored, of every run.
+func (c *GenericCompressor[T]) runs(values []T, f func(first, last T) error) error {
+	start := 0
+	for i := 1; i <= len(values); i++ {
+		if i < len(values) && c.distance(c.storedValue(values, i-1), c.storedValue(values, i)) == 1 {
+			continue
+		}
+		if err := f(c.storedValue(values, start), c.storedValue(values, i-1)); err != nil {
+			return err
+		}
+		start = i
+	}
+	return nil
+}
+
+func (c *GenericCompressor[T]) runsBitsLen(values []T) int {
+	bits := wordSize[T]()
+	n := 0
+	var prev T
+	c.runs(values, func(first, last T) error {
+		if n > 0 {
+			bits += sizedBitsLen(T(c.distance(prev, first) - 1))
+		}
+		bits += sizedBitsLen(T(c.distance(first, last)))
+		n++
+		prev = last
+		return nil
+	})
+	return bits
+}
+
+func (c *GenericCompressor[T]) writeSized(x T) error {
+	w := bitsLen(x)
+	if err := c.writer.Write(uint64(w-1), deltaWidthHeaderSize[T]()); err != nil {
+		return err
+	}
+	return c.writer.Write(uint64(x), w)
+}
+
+func (c *GenericCompressor[T]) writeRuns(values []T) error {
+	n := 0
+	var prev T
+	return c.runs(values, func(first, last T) error {
+		if n == 0 {
+			if err := c.writer.Write(uint64(first), wordSize[T]()); err != nil {
+				return err
+			}
+		} else if err := c.writeSized(T(c.distance(prev, first) - 1)); err != nil {
+			return err
+		}
+		n++
+		prev = last
+		return c.writeSized(T(c.distance(first, last)))
+	})
+}
+
+// runReader walks the values of a run container. next is the next value of
+// the current run, which has left values to go, and remaining is the number
+// of values left in the container.
+type runReader[T Unsigned] struct {
+	ascending bool
+	remaining int
+	runs      int
+	next      T
+	last      T
+	left      int
+}
+
+func (d *GenericDecompressor[T]) readSized() (T, error) {
+	w, err := d.read(deltaWidthHeaderSize[T]())
+	if err != nil {
+		return 0, err
+	}
+	x, err := d.read(int(w) + 1)
+	if err != nil {
+		return 0, err
+	}
+	return T(x), nil
+}
+
+func (d *GenericDecompressor[T]) readRun(r *runReader[T]) error {
+	var start T
+	if r.runs == 0 {
+		v, err := d.read(wordSize[T]())
+		if err != nil {
+			return err
+		}
+		start = T(v)
+	} else {
+		g, err := d.readSized()
+		if err != nil {
+			return err
+		}
+		if r.ascending && g >= ^T(0)-r.last || !r.ascending && g >= r.last {
+			return ErrCorruptInput
+		}
+		start = applyGap(r.last, g+1, d.storedOrder())
+	}
+
+	l, err := d.readSized()
+	if err != nil {
+		return err
+	}
+	if uint64(l) >= uint64(r.remaining) || r.ascending && l > ^T(0)-start || !r.ascending && l > start {
+		return ErrCorruptInput
+	}
+
+	r.runs++
+	r.next = start
+	r.last = applyGap(start, l, d.storedOrder())
+	r.left = int(l) + 1
+	return nil
+}
+
+func (d *GenericDecompressor[T]) readRunValue(r *runReader[T]) (T, error) {
+	if r.left == 0 {
+		if err := d.readRun(r); err != nil {
+			return 0, err
+		}
+	}
+
+	v := r.next
+	r.next = applyGap(v, 1, d.storedOrder())
+	r.left--
+	r.remaining--
+	return v, nil
+}
+
+// storedOrder returns the order values are stored in.
+func (d *GenericDecompressor[T]) storedOrder() int {
+	if d.storedAscending() {
+		return OrderAscending
+	}
+	return OrderDescending
+}
+
+func (d *GenericDecompressor[T]) readValuesRuns(output []T) ([]T, error) {
+	r := runReader[T]{ascending: d.storedAscending(), remaining: len(output)}
+	for i := range output {
+		v, err := d.readRunValue(&r)
+		if err != nil {
+			return nil, err
+		}
+		d.setStored(output, i, v)
+	}
+	return output, nil
+}
@@ -0,0 +1,345 @@
+package simple
+
+import (
+	"encoding/binary"
+	"math/rand"
+	"slices"
+	"testing"
+
+	"github.com/vteromero/playground/simple-integer-list-compression/slice"
+	"github.com/stretchr/testify/assert"
+)
+
+// runsUint32Slice returns an ascending list of n values made of runs of
+// consecutive values, with about a third of them being single values.
+func runsUint32Slice(r *rand.Rand, n int) []uint32 {
+	s := make([]uint32, 0, n)
+	v := uint32(r.Intn(1000))
+	for len(s) < n {
+		runLen := 1
+		if r.Intn(3) > 0 {
+			runLen = 1 + r.Intn(200)
+		}
+		for i := 0; i < runLen && len(s) < n; i++ {
+			s = append(s, v)
+			v++
+		}
+		v += 1 + uint32(r.Intn(1000))
+	}
+	return s
+}
+
+func TestCompressor_CompressRuns(t *testing.T) {
+	params := []struct {
+		order     int
+		hybrid    bool
+		input     []uint32
+		expectedN int
+	}{
+		{OrderAscending, false, []uint32{}, 16},
+		{OrderAscending, false, rangeUint32Slice(1000, 1000), 16 + 1 + 32 + (5 + 10)},
+		{OrderAscending, true, rangeUint32Slice(1000, 1000), 16 + 2 + 32 + (5 + 10)},
+		{OrderDescending, false, slice.SortDescUint32Slice(rangeUint32Slice(1000, 1000)), 16 + 1 + 32 + (5 + 10)},
+		{OrderAscending, false, append(rangeUint32Slice(10, 100), rangeUint32Slice(5000, 100)...), 16 + 1 + 32 + (5 + 7) + (5 + 13) + (5 + 7)},
+		{OrderAscending, false, []uint32{1, 2, 3, 10, 20, 21, 22}, 16 + 1 + 55},
+		{OrderAscending, false, []uint32{7, 7, 7, 7, 7, 7, 7, 7, 7, 7, 7, 7, 7, 7, 7, 7, 7, 7, 7, 7, 7, 7}, 16 + 1 + 32 + 21*3},
+	}
+
+	for _, testCase := range params {
+		c := NewCompressor(testCase.order, 16)
+		c.Runs = true
+		c.Hybrid = testCase.hybrid
+		output := make([]byte, c.MaxCompressedLen(len(testCase.input)))
+		m, err := c.Compress(testCase.input, output)
+		assert.Nil(t, err)
+		assert.Equal(t, testCase.expectedN, m)
+
+		d := NewDecompressor(testCase.order, 16)
+		d.Runs = true
+		d.Hybrid = testCase.hybrid
+		decompressed, err := d.Decompress(output[:sizeInBytes(m)])
+		assert.Nil(t, err)
+		assert.Equal(t, testCase.input, decompressed)
+	}
+}
+
+func TestCompressAndDecompressRuns(t *testing.T) {
+	params := []struct {
+		order     int
+		inputSize int
+		runs      bool
+		blockSize int
+		hybrid    bool
+		delta     bool
+		forward   bool
+		adaptive  bool
+		framed    bool
+	}{
+		{OrderAscending, 0, true, 0, false, false, false, false, false},
+		{OrderAscending, 1, true, 0, false, false, false, false, false},
+		{OrderAscending, 1000, true, 0, false, false, false, false, false},
+		{OrderAscending, 1000, false, 0, false, false, false, false, false},
+		{OrderAscending, 1000, true, 0, true, false, false, false, false},
+		{OrderAscending, 1000, false, 0, true, false, false, false, false},
+		{OrderAscending, 1000, true, 0, false, true, false, false, false},
+		{OrderAscending, 1000, true, 0, false, false, true, false, false},
+		{OrderAscending, 1000, true, 0, false, false, false, true, false},
+		{OrderAscending, 1000, true, 0, true, false, false, false, true},
+		{OrderAscending, 1000, true, DefaultBlockSize, false, false, false, false, false},
+		{OrderAscending, 1000, false, DefaultBlockSize, true, true, false, false, true},
+		{OrderAscending, 1000, true, 1, true, false, false, false, false},
+		{OrderDescending, 1, true, 0, false, false, false, false, false},
+		{OrderDescending, 1000, true, 0, false, false, false, false, false},
+		{OrderDescending, 1000, false, 0, true, false, false, false, false},
+		{OrderDescending, 1000, true, 0, true, true, false, false, false},
+		{OrderDescending, 1000, true, 0, false, false, false, true, true},
+		{OrderDescending, 1000, true, DefaultBlockSize, true, false, false, false, false},
+	}
+
+	r := rand.New(rand.NewSource(1))
+	for _, testCase := range params {
+		var input []uint32
+		if testCase.runs {
+			input = runsUint32Slice(r, testCase.inputSize)
+		} else {
+			input = slice.SortAscUint32Slice(slice.RandomUint32SliceFrom(r, testCase.inputSize))
+		}
+		if testCase.order == OrderDescending {
+			input = slice.SortDescUint32Slice(input)
+		}
+
+		c := NewCompressor(testCase.order, 32)
+		c.Runs = true
+		c.Hybrid = testCase.hybrid
+		c.BlockSize = testCase.blockSize
+		c.Delta = testCase.delta
+		c.Forward = testCase.forward
+		c.Adaptive = testCase.adaptive
+		c.Framed = testCase.framed
+		compOutput := make([]byte, c.MaxCompressedLen(testCase.inputSize))
+		_, err := c.Compress(input, compOutput)
+		assert.Nil(t, err)
+
+		var d *Decompressor
+		if testCase.framed {
+			d = NewFramedDecompressor()
+		} else {
+			d = NewDecompressor(testCase.order, 32)
+			d.Runs = true
+			d.Hybrid = testCase.hybrid
+			d.Blocked = testCase.blockSize > 0
+			d.Delta = testCase.delta
+			d.Forward = testCase.forward
+			d.Adaptive = testCase.adaptive
+		}
+		output, err := d.Decompress(compOutput)
+		assert.Nil(t, err)
+		assert.Equal(t, input, output)
+
+		it, err := d.Iter(compOutput)
+		assert.Nil(t, err)
+		expected := slices.Clone(input)
+		if it.ListOrder != testCase.order {
+			slices.Reverse(expected)
+		}
+		iterOutput := slices.Collect(it.All())
+		assert.Nil(t, it.Err())
+		assert.Equal(t, len(expected), len(iterOutput))
+		if len(expected) > 0 {
+			assert.Equal(t, expected, iterOutput)
+		}
+	}
+}
+
+func TestCompressAndDecompressRuns64(t *testing.T) {
+	input := []uint64{1<<64 - 5, 1<<64 - 4, 1<<64 - 3, 1<<64 - 2, 1<<64 - 1}
+
+	for _, order := range []int{OrderAscending, OrderDescending} {
+		if order == OrderDescending {
+			input = slice.SortDescUint64Slice(input)
+		}
+
+		c := NewCompressor64(order, 32)
+		c.Runs = true
+		c.Framed = true
+		compOutput := make([]byte, c.MaxCompressedLen(len(input)))
+		_, err := c.Compress(input, compOutput)
+		assert.Nil(t, err)
+
+		output, err := NewFramedDecompressor64().Decompress(compOutput)
+		assert.Nil(t, err)
+		assert.Equal(t, input, output)
+	}
+}
+
+func TestCompressRunsIsSmallerOnRunInput(t *testing.T) {
+	input := runsUint32Slice(rand.New(rand.NewSource(1)), 10000)
+
+	plain := NewCompressor(OrderAscending, 32)
+	plain.Delta = true
+	plainN, err := plain.Compress(input, make([]byte, plain.MaxCompressedLen(len(input))))
+	assert.Nil(t, err)
+
+	c := NewCompressor(OrderAscending, 32)
+	c.Delta = true
+	c.Runs = true
+	runsN, err := c.Compress(input, make([]byte, c.MaxCompressedLen(len(input))))
+	assert.Nil(t, err)
+	assert.Less(t, runsN, plainN)
+}
+
+func TestDecompressor_DecompressRunsCorrupt(t *testing.T) {
+	c := NewCompressor(OrderAscending, 8)
+	c.Runs = true
+	compOutput := make([]byte, c.MaxCompressedLen(100))
+	n, err := c.Compress(rangeUint32Slice(1000, 100), compOutput)
+	assert.Nil(t, err)
+	compOutput = compOutput[:sizeInBytes(n)]
+
+	// the run is longer than the list
+	shorter := slices.Clone(compOutput)
+	shorter[0] = 50
+
+	// the list needs a second run that is not there
+	longer := slices.Clone(compOutput)
+	longer[0] = 101
+
+	params := []struct {
+		input          []byte
+		maxCardinality int
+		expectedErr    error
+	}{
+		{compOutput, 1 << 8, nil},
+		{compOutput, 0, nil},
+		{compOutput, 99, ErrCardinalityTooLarge},
+		{shorter, 1 << 8, ErrCorruptInput},
+		{longer, 1 << 8, ErrTruncatedInput},
+		{compOutput[:4], 1 << 8, ErrTruncatedInput},
+	}
+
+	for _, testCase := range params {
+		d := NewDecompressor(OrderAscending, 8)
+		d.Runs = true
+		d.MaxCardinality = testCase.maxCardinality
+		_, err := d.Decompress(testCase.input)
+		assert.Equal(t, testCase.expectedErr, err)
+
+		it, err := d.Iter(testCase.input)
+		if err != nil {
+			assert.Equal(t, testCase.expectedErr, err)
+			continue
+		}
+		for range it.All() {
+		}
+		assert.Equal(t, testCase.expectedErr, it.Err())
+	}
+}
+
+func TestDecompressor_DecompressLongRuns(t *testing.T) {
+	params := []struct {
+		inputSize      int
+		maxCardinality int
+	}{
+		{50000, 0},
+		{100000, 1 << 17},
+	}
+
+	for _, testCase := range params {
+		input := rangeUint32Slice(1000, testCase.inputSize)
+
+		for _, framed := range []bool{false, true} {
+			c := NewCompressor(OrderAscending, 32)
+			c.Runs = true
+			c.Framed = framed
+			c.MaxCardinality = testCase.maxCardinality
+			compOutput := make([]byte, c.MaxCompressedLen(len(input)))
+			n, err := c.Compress(input, compOutput)
+			assert.Nil(t, err)
+			compOutput = compOutput[:sizeInBytes(n)]
+
+			d := NewFramedDecompressor()
+			if !framed {
+				d = NewDecompressor(OrderAscending, 32)
+				d.Runs = true
+			}
+			d.MaxCardinality = testCase.maxCardinality
+
+			output, err := d.Decompress(compOutput)
+			assert.Nil(t, err)
+			assert.Equal(t, input, output)
+
+			cardinality, err := d.Cardinality(compOutput)
+			assert.Nil(t, err)
+			assert.Equal(t, len(input), cardinality)
+
+			it, err := d.Iter(compOutput)
+			assert.Nil(t, err)
+			assert.Equal(t, len(input), len(slices.Collect(it.All())))
+			assert.Nil(t, it.Err())
+		}
+	}
+}
+
+func TestCompressor_CompressRunsTooLarge(t *testing.T) {
+	params := []struct {
+		input          []uint32
+		maxCardinality int
+		expectedErr    error
+	}{
+		// runs of more values than DefaultMaxCardinality take a few bytes
+		{rangeUint32Slice(1000, DefaultMaxCardinality+1), 0, ErrCardinalityTooLarge},
+		{rangeUint32Slice(1000, DefaultMaxCardinality+1), DefaultMaxCardinality + 1, nil},
+		{rangeUint32Slice(1000, 1000), 999, ErrCardinalityTooLarge},
+		// lists taking more bits than values are not bounded by default
+		{slice.RandomSortedSet(rand.New(rand.NewSource(1)), DefaultMaxCardinality+1, 1<<32), 0, nil},
+	}
+
+	for _, testCase := range params {
+		c := NewCompressor(OrderAscending, 32)
+		c.Runs = true
+		c.Framed = true
+		c.MaxCardinality = testCase.maxCardinality
+		compOutput := make([]byte, c.MaxCompressedLen(len(testCase.input)))
+		n, err := c.Compress(testCase.input, compOutput)
+		assert.Equal(t, testCase.expectedErr, err)
+		if err != nil {
+			continue
+		}
+
+		// what compresses decompresses with the same bound
+		d := NewFramedDecompressor()
+		d.MaxCardinality = testCase.maxCardinality
+		_, err = d.Decompress(compOutput[:sizeInBytes(n)])
+		assert.Nil(t, err)
+	}
+}
+
+func TestDecompressor_DecompressRunsTooLarge(t *testing.T) {
+	c := NewCompressor(OrderAscending, 32)
+	c.Runs = true
+	c.Framed = true
+	compOutput := make([]byte, c.MaxCompressedLen(1000))
+	n, err := c.Compress(rangeUint32Slice(1000, 1000), compOutput)
+	assert.Nil(t, err)
+	compOutput = compOutput[:sizeInBytes(n)]
+
+	// the frame turns runs on, and the list claims more values than
+	// DefaultMaxCardinality in a few bytes
+	binary.LittleEndian.PutUint32(compOutput[frameHeaderLen:], DefaultMaxCardinality+1)
+	binary.LittleEndian.PutUint32(compOutput[12:], checksum(compOutput[frameHeaderLen:]))
+
+	d := NewFramedDecompressor()
+	_, err = d.Decompress(compOutput)
+	assert.Equal(t, ErrCardinalityTooLarge, err)
+
+	_, err = d.Cardinality(compOutput)
+	assert.Equal(t, ErrCardinalityTooLarge, err)
+
+	_, err = d.Iter(compOutput)
+	assert.Equal(t, ErrCardinalityTooLarge, err)
+
+	// MaxCardinality takes over from the default bound
+	d.MaxCardinality = DefaultMaxCardinality / 2
+	_, err = d.Decompress(compOutput)
+	assert.Equal(t, ErrCardinalityTooLarge, err)
+}
@@ -0,0 +1,151 @@
+package setops
+
+import (
+	"math/rand"
+	"sync"
+	"testing"
+
+	"github.com/vteromero/playground/simple-integer-list-compression"
+	"github.com/vteromero/playground/simple-integer-list-compression/slice"
+)
+
+// benchmarkSeed makes every run benchmark the same data.
+const benchmarkSeed = 1
+
+func compressBench(c *simple.Compressor, values []uint32) []byte {
+	output := make([]byte, c.MaxCompressedLen(len(values)))
+	n, err := c.Compress(values, output)
+	if err != nil {
+		panic(err)
+	}
+	return output[:(n+7)/8]
+}
+
+// The linear merges below are the baseline that the set operations are
+// measured against: both lists decoded, then merged value by value.
+
+func linearIntersect(a, b []uint32) []uint32 {
+	out := make([]uint32, 0, min(len(a), len(b)))
+	i, j := 0, 0
+	for i < len(a) && j < len(b) {
+		switch {
+		case a[i] < b[j]:
+			i++
+		case a[i] > b[j]:
+			j++
+		default:
+			out = append(out, a[i])
+			i++
+			j++
+		}
+	}
+	return out
+}
+
+func linearUnion(a, b []uint32) []uint32 {
+	out := make([]uint32, 0, len(a)+len(b))
+	i, j := 0, 0
+	for i < len(a) && j < len(b) {
+		switch {
+		case a[i] < b[j]:
+			out = append(out, a[i])
+			i++
+		case a[i] > b[j]:
+			out = append(out, b[j])
+			j++
+		default:
+			out = append(out, a[i])
+			i++
+			j++
+		}
+	}
+	out = append(out, a[i:]...)
+	return append(out, b[j:]...)
+}
+
+func linearDifference(a, b []uint32) []uint32 {
+	out := make([]uint32, 0, len(a))
+	j := 0
+	for _, v := range a {
+		for j < len(b) && b[j] < v {
+			j++
+		}
+		if j == len(b) || b[j] != v {
+			out = append(out, v)
+		}
+	}
+	return out
+}
+
+var (
+	// the sets are built on first use, so that tests do not pay for them
+	setsOnce sync.Once
+	smallSet []uint32
+	largeSet []uint32
+)
+
+func benchmarkSets() ([]uint32, []uint32) {
+	setsOnce.Do(func() {
+		r := rand.New(rand.NewSource(benchmarkSeed))
+		smallSet = slice.RandomSortedSet(r, 100, 1<<24)
+		largeSet = slice.RandomSortedSet(r, 100000, 1<<24)
+	})
+	return smallSet, largeSet
+}
+
+var benchmarkLayouts = []struct {
+	name      string
+	blockSize int
+}{
+	{"blocked", simple.DefaultBlockSize},
+	{"plain", 0},
+}
+
+type setOp func(d *simple.Decompressor, c *simple.Compressor, a, b []byte) ([]byte, error)
+
+// benchmarkSetOp measures op on the small and large sets, and then
+// decompressing both sets, merging them with merge and compressing the
+// result.
+func benchmarkSetOp(b *testing.B, op setOp, merge func(a, b []uint32) []uint32) {
+	small, large := benchmarkSets()
+	for _, layout := range benchmarkLayouts {
+		c := simple.NewCompressor(simple.OrderAscending, 32)
+		c.BlockSize = layout.blockSize
+		d := simple.NewDecompressor(simple.OrderAscending, 32)
+		d.Blocked = layout.blockSize > 0
+		blobA := compressBench(c, small)
+		blobB := compressBench(c, large)
+
+		b.Run(layout.name, func(b *testing.B) {
+			b.ReportAllocs()
+			b.ResetTimer()
+
+			for i := 0; i < b.N; i++ {
+				op(d, c, blobA, blobB)
+			}
+		})
+
+		b.Run(layout.name+"/decompress-and-merge", func(b *testing.B) {
+			b.ReportAllocs()
+			b.ResetTimer()
+
+			for i := 0; i < b.N; i++ {
+				x, _ := d.Decompress(blobA)
+				y, _ := d.Decompress(blobB)
+				compressBench(c, merge(x, y))
+			}
+		})
+	}
+}
+
+func BenchmarkIntersect(b *testing.B) {
+	benchmarkSetOp(b, Intersect, linearIntersect)
+}
+
+func BenchmarkUnion(b *testing.B) {
+	benchmarkSetOp(b, Union, linearUnion)
+}
+
+func BenchmarkDifference(b *testing.B) {
+	benchmarkSetOp(b, Difference, linearDifference)
+}
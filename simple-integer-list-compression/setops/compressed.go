@@ -0,0 +1,176 @@
+package setops
+
+import (
+	"github.com/vteromero/playground/simple-integer-list-compression"
+)
+
+func reverse(s []uint32) []uint32 {
+	for i, j := 0, len(s)-1; i < j; i, j = i+1, j-1 {
+		s[i], s[j] = s[j], s[i]
+	}
+	return s
+}
+
+func compress(c *simple.Compressor, values []uint32, order int) ([]byte, error) {
+	if c.ListOrder != order {
+		values = reverse(values)
+	}
+
+	output := make([]byte, c.MaxCompressedLen(len(values)))
+	n, err := c.Compress(values, output)
+	if err != nil {
+		return nil, err
+	}
+
+	return output[:(n+7)/8], nil
+}
+
+func decompress(d *simple.Decompressor, a, b []byte) ([]uint32, []uint32, int, error) {
+	x, err := d.Decompress(a)
+	if err != nil {
+		return nil, nil, 0, err
+	}
+	order := d.ListOrder
+
+	y, err := d.Decompress(b)
+	if err != nil {
+		return nil, nil, 0, err
+	}
+	if d.ListOrder != order {
+		return nil, nil, 0, ErrOrderMismatch
+	}
+
+	return x, y, order, nil
+}
+
+// open returns a view over blob, or nil if blob is not blocked.
+func open(d *simple.Decompressor, blob []byte) (*simple.BlockList, error) {
+	l, err := d.Open(blob)
+	if err == simple.ErrNotBlocked {
+		return nil, nil
+	}
+	return l, err
+}
+
+func get(l *simple.BlockList, i int) (uint32, bool, error) {
+	if i >= l.Len() {
+		return 0, false, nil
+	}
+	v, err := l.Get(i)
+	return v, err == nil, err
+}
+
+// contains looks value up in l by seeking, which only decodes the block that
+// may hold it.
+func contains(l *simple.BlockList, value uint32) (bool, error) {
+	i, err := l.Seek(value)
+	if err != nil {
+		return false, err
+	}
+	v, ok, err := get(l, i)
+	return ok && v == value, err
+}
+
+func intersectBlocks(la, lb *simple.BlockList) ([]uint32, error) {
+	if la.ListOrder != lb.ListOrder {
+		return nil, ErrOrderMismatch
+	}
+
+	if la.Len() > lb.Len() {
+		la, lb = lb, la
+	}
+
+	out := make([]uint32, 0, la.Len())
+	for i := 0; i < la.Len(); i++ {
+		v, err := la.Get(i)
+		if err != nil {
+			return nil, err
+		}
+
+		found, err := contains(lb, v)
+		if err != nil {
+			return nil, err
+		}
+		if found {
+			out = appendUnique(out, v)
+		}
+	}
+	return out, nil
+}
+
+// Intersect decodes a and b with d and returns the values present in both,
+// encoded with c. When both lists are blocked, the smaller list is decoded and
+// its values are looked up in the larger one, so that only the blocks of the
+// larger list that may hold them are decoded.
+func Intersect(d *simple.Decompressor, c *simple.Compressor, a, b []byte) ([]byte, error) {
+	la, err := open(d, a)
+	if err != nil {
+		return nil, err
+	}
+	lb, err := open(d, b)
+	if err != nil {
+		return nil, err
+	}
+
+	if la != nil && lb != nil {
+		out, err := intersectBlocks(la, lb)
+		if err != nil {
+			return nil, err
+		}
+		return compress(c, out, la.ListOrder)
+	}
+
+	x, y, order, err := decompress(d, a, b)
+	if err != nil {
+		return nil, err
+	}
+	return compress(c, intersect(x, y, order), order)
+}
+
+// Union decodes a and b with d and returns the values present in any of
+// them, encoded with c.
+func Union(d *simple.Decompressor, c *simple.Compressor, a, b []byte) ([]byte, error) {
+	x, y, order, err := decompress(d, a, b)
+	if err != nil {
+		return nil, err
+	}
+	return compress(c, union(x, y, order), order)
+}
+
+// Difference decodes a and b with d and returns the values of a that are not
+// in b, encoded with c. When b is blocked, only the blocks of b that may hold
+// values of a are decoded.
+func Difference(d *simple.Decompressor, c *simple.Compressor, a, b []byte) ([]byte, error) {
+	lb, err := open(d, b)
+	if err != nil {
+		return nil, err
+	}
+
+	if lb == nil {
+		x, y, order, err := decompress(d, a, b)
+		if err != nil {
+			return nil, err
+		}
+		return compress(c, difference(x, y, order), order)
+	}
+
+	x, err := d.Decompress(a)
+	if err != nil {
+		return nil, err
+	}
+	if d.ListOrder != lb.ListOrder {
+		return nil, ErrOrderMismatch
+	}
+
+	out := make([]uint32, 0, len(x))
+	for _, v := range x {
+		found, err := contains(lb, v)
+		if err != nil {
+			return nil, err
+		}
+		if !found {
+			out = appendUnique(out, v)
+		}
+	}
+	return compress(c, out, lb.ListOrder)
+}
@@ -0,0 +1,97 @@
+package setops
+
+// The iterator variants merge a and b as they are consumed, so neither list
+// needs to be fully decoded up front. Both iterators must yield values in
+// the given order. If either of them stops on an error, that error is
+// returned instead of a partial result.
+
+type cursor struct {
+	it    Iterator
+	value uint32
+	ok    bool
+}
+
+func newCursor(it Iterator) *cursor {
+	c := &cursor{it: it}
+	c.next()
+	return c
+}
+
+func (c *cursor) next() {
+	c.value, c.ok = c.it.Next()
+}
+
+// iteratorsErr returns the error, if any, that stopped a or b.
+func iteratorsErr(a, b Iterator) error {
+	if err := a.Err(); err != nil {
+		return err
+	}
+	return b.Err()
+}
+
+func IntersectIterators(a, b Iterator, order int) ([]uint32, error) {
+	bf := before(order)
+	ca, cb := newCursor(a), newCursor(b)
+	var out []uint32
+	for ca.ok && cb.ok {
+		switch {
+		case bf(ca.value, cb.value):
+			ca.next()
+		case bf(cb.value, ca.value):
+			cb.next()
+		default:
+			out = appendUnique(out, ca.value)
+			ca.next()
+			cb.next()
+		}
+	}
+	if err := iteratorsErr(a, b); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func UnionIterators(a, b Iterator, order int) ([]uint32, error) {
+	bf := before(order)
+	ca, cb := newCursor(a), newCursor(b)
+	var out []uint32
+	for ca.ok || cb.ok {
+		switch {
+		case !cb.ok || ca.ok && bf(ca.value, cb.value):
+			out = appendUnique(out, ca.value)
+			ca.next()
+		case !ca.ok || bf(cb.value, ca.value):
+			out = appendUnique(out, cb.value)
+			cb.next()
+		default:
+			out = appendUnique(out, ca.value)
+			ca.next()
+			cb.next()
+		}
+	}
+	if err := iteratorsErr(a, b); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func DifferenceIterators(a, b Iterator, order int) ([]uint32, error) {
+	bf := before(order)
+	ca, cb := newCursor(a), newCursor(b)
+	var out []uint32
+	for ca.ok {
+		switch {
+		case !cb.ok || bf(ca.value, cb.value):
+			out = appendUnique(out, ca.value)
+			ca.next()
+		case bf(cb.value, ca.value):
+			cb.next()
+		default:
+			ca.next()
+		}
+	}
+	if err := iteratorsErr(a, b); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
@@ -0,0 +1,139 @@
+// Package setops implements set operations over sorted lists compressed with
+// the simple codec. Results are sets, that is, duplicated values show up only
+// once.
+package setops
+
+import (
+	"errors"
+
+	"github.com/vteromero/playground/simple-integer-list-compression"
+)
+
+var ErrOrderMismatch = errors.New("setops: lists have different order")
+
+// Iterator yields the values of a list. Next returns false once the list is
+// exhausted or an error occurred, which Err then returns.
+type Iterator interface {
+	Next() (uint32, bool)
+	Err() error
+}
+
+func before(order int) func(x, y uint32) bool {
+	if order == simple.OrderDescending {
+		return func(x, y uint32) bool { return x > y }
+	}
+	return func(x, y uint32) bool { return x < y }
+}
+
+// gallop returns the first position j >= lo such that s[j] is not before
+// target, probing positions lo+1, lo+3, lo+7, ... before falling back to a
+// binary search.
+func gallop(s []uint32, lo int, target uint32, before func(x, y uint32) bool) int {
+	if lo >= len(s) || !before(s[lo], target) {
+		return lo
+	}
+
+	step := 1
+	hi := lo + step
+	for hi < len(s) && before(s[hi], target) {
+		lo = hi
+		step *= 2
+		hi = lo + step
+	}
+	if hi > len(s) {
+		hi = len(s)
+	}
+
+	// s[lo] is before target and s[hi] (if any) is not
+	for lo+1 < hi {
+		mid := int(uint(lo+hi) >> 1)
+		if before(s[mid], target) {
+			lo = mid
+		} else {
+			hi = mid
+		}
+	}
+	return hi
+}
+
+func appendUnique(out []uint32, v uint32) []uint32 {
+	if len(out) > 0 && out[len(out)-1] == v {
+		return out
+	}
+	return append(out, v)
+}
+
+func intersect(a, b []uint32, order int) []uint32 {
+	if len(a) > len(b) {
+		a, b = b, a
+	}
+
+	bf := before(order)
+	out := make([]uint32, 0, len(a))
+	j := 0
+	for _, v := range a {
+		j = gallop(b, j, v, bf)
+		if j == len(b) {
+			break
+		}
+		if b[j] == v {
+			out = appendUnique(out, v)
+		}
+	}
+	return out
+}
+
+func union(a, b []uint32, order int) []uint32 {
+	bf := before(order)
+	out := make([]uint32, 0, len(a)+len(b))
+	i, j := 0, 0
+	for i < len(a) && j < len(b) {
+		switch {
+		case bf(a[i], b[j]):
+			out = appendUnique(out, a[i])
+			i++
+		case bf(b[j], a[i]):
+			out = appendUnique(out, b[j])
+			j++
+		default:
+			out = appendUnique(out, a[i])
+			i++
+			j++
+		}
+	}
+	for ; i < len(a); i++ {
+		out = appendUnique(out, a[i])
+	}
+	for ; j < len(b); j++ {
+		out = appendUnique(out, b[j])
+	}
+	return out
+}
+
+func difference(a, b []uint32, order int) []uint32 {
+	bf := before(order)
+	out := make([]uint32, 0, len(a))
+	j := 0
+	for _, v := range a {
+		j = gallop(b, j, v, bf)
+		if j == len(b) || b[j] != v {
+			out = appendUnique(out, v)
+		}
+	}
+	return out
+}
+
+// IntersectSlices returns the values present in both ascending lists a and b.
+func IntersectSlices(a, b []uint32) []uint32 {
+	return intersect(a, b, simple.OrderAscending)
+}
+
+// UnionSlices returns the values present in any of the ascending lists a and b.
+func UnionSlices(a, b []uint32) []uint32 {
+	return union(a, b, simple.OrderAscending)
+}
+
+// DifferenceSlices returns the values of the ascending list a that are not in b.
+func DifferenceSlices(a, b []uint32) []uint32 {
+	return difference(a, b, simple.OrderAscending)
+}
@@ -0,0 +1,242 @@
+package setops
+
+import (
+	"math/rand"
+	"sort"
+	"testing"
+
+	"github.com/vteromero/playground/simple-integer-list-compression"
+	"github.com/stretchr/testify/assert"
+)
+
+func randomSet(n int, universe uint32, order int) []uint32 {
+	seen := make(map[uint32]bool, n)
+	s := make([]uint32, 0, n)
+	for len(s) < n {
+		v := rand.Uint32() % universe
+		if !seen[v] {
+			seen[v] = true
+			s = append(s, v)
+		}
+	}
+	sort.Slice(s, func(i, j int) bool {
+		if order == simple.OrderDescending {
+			return s[i] > s[j]
+		}
+		return s[i] < s[j]
+	})
+	return s
+}
+
+func reference(a, b []uint32, order int, keep func(inA, inB bool) bool) []uint32 {
+	inA := make(map[uint32]bool, len(a))
+	inB := make(map[uint32]bool, len(b))
+	for _, v := range a {
+		inA[v] = true
+	}
+	for _, v := range b {
+		inB[v] = true
+	}
+
+	out := []uint32{}
+	for v := range inA {
+		if keep(true, inB[v]) {
+			out = append(out, v)
+		}
+	}
+	for v := range inB {
+		if !inA[v] && keep(false, true) {
+			out = append(out, v)
+		}
+	}
+	sort.Slice(out, func(i, j int) bool {
+		if order == simple.OrderDescending {
+			return out[i] > out[j]
+		}
+		return out[i] < out[j]
+	})
+	return out
+}
+
+func both(inA, inB bool) bool   { return inA && inB }
+func either(inA, inB bool) bool { return inA || inB }
+func onlyA(inA, inB bool) bool  { return inA && !inB }
+
+type sliceIterator struct {
+	s []uint32
+}
+
+func (it *sliceIterator) Next() (uint32, bool) {
+	if len(it.s) == 0 {
+		return 0, false
+	}
+	v := it.s[0]
+	it.s = it.s[1:]
+	return v, true
+}
+
+func (it *sliceIterator) Err() error {
+	return nil
+}
+
+var setopsParams = []struct {
+	order    int
+	sizeA    int
+	sizeB    int
+	universe uint32
+}{
+	{simple.OrderAscending, 0, 0, 100},
+	{simple.OrderAscending, 0, 10, 100},
+	{simple.OrderAscending, 10, 0, 100},
+	{simple.OrderAscending, 50, 50, 100},
+	{simple.OrderAscending, 10, 1000, 5000},
+	{simple.OrderAscending, 1000, 1000, 1 << 31},
+	{simple.OrderDescending, 0, 10, 100},
+	{simple.OrderDescending, 50, 50, 100},
+	{simple.OrderDescending, 1000, 10, 5000},
+}
+
+func TestSlices(t *testing.T) {
+	for _, testCase := range setopsParams {
+		if testCase.order != simple.OrderAscending {
+			continue
+		}
+		a := randomSet(testCase.sizeA, testCase.universe, testCase.order)
+		b := randomSet(testCase.sizeB, testCase.universe, testCase.order)
+
+		assert.Equal(t, reference(a, b, testCase.order, both), append([]uint32{}, IntersectSlices(a, b)...))
+		assert.Equal(t, reference(a, b, testCase.order, either), append([]uint32{}, UnionSlices(a, b)...))
+		assert.Equal(t, reference(a, b, testCase.order, onlyA), append([]uint32{}, DifferenceSlices(a, b)...))
+	}
+}
+
+func TestSlicesDuplicates(t *testing.T) {
+	a := []uint32{1, 1, 2, 5, 5, 5, 9}
+	b := []uint32{1, 5, 5, 7, 7}
+
+	assert.Equal(t, []uint32{1, 5}, IntersectSlices(a, b))
+	assert.Equal(t, []uint32{1, 2, 5, 7, 9}, UnionSlices(a, b))
+	assert.Equal(t, []uint32{2, 9}, DifferenceSlices(a, b))
+}
+
+func TestIterators(t *testing.T) {
+	for _, testCase := range setopsParams {
+		a := randomSet(testCase.sizeA, testCase.universe, testCase.order)
+		b := randomSet(testCase.sizeB, testCase.universe, testCase.order)
+
+		got, err := IntersectIterators(&sliceIterator{a}, &sliceIterator{b}, testCase.order)
+		assert.Nil(t, err)
+		assert.Equal(t, reference(a, b, testCase.order, both), append([]uint32{}, got...))
+		got, err = UnionIterators(&sliceIterator{a}, &sliceIterator{b}, testCase.order)
+		assert.Nil(t, err)
+		assert.Equal(t, reference(a, b, testCase.order, either), append([]uint32{}, got...))
+		got, err = DifferenceIterators(&sliceIterator{a}, &sliceIterator{b}, testCase.order)
+		assert.Nil(t, err)
+		assert.Equal(t, reference(a, b, testCase.order, onlyA), append([]uint32{}, got...))
+	}
+}
+
+func TestIteratorsCorrupt(t *testing.T) {
+	c := simple.NewCompressor(simple.OrderDescending, 32)
+	d := simple.NewDecompressor(simple.OrderDescending, 32)
+	a := randomSet(1000, 1<<31, simple.OrderDescending)
+	blob := compressSet(t, c, a)
+	truncated := blob[:len(blob)/2]
+
+	ops := []func(a, b Iterator, order int) ([]uint32, error){
+		IntersectIterators,
+		UnionIterators,
+		DifferenceIterators,
+	}
+
+	for _, op := range ops {
+		itA, err := d.Iter(truncated)
+		assert.Nil(t, err)
+		itB, err := d.Iter(blob)
+		assert.Nil(t, err)
+		got, err := op(itA, itB, simple.OrderDescending)
+		assert.Equal(t, simple.ErrTruncatedInput, err)
+		assert.Nil(t, got)
+
+		itA, err = d.Iter(blob)
+		assert.Nil(t, err)
+		itB, err = d.Iter(truncated)
+		assert.Nil(t, err)
+		got, err = op(itA, itB, simple.OrderDescending)
+		assert.Equal(t, simple.ErrTruncatedInput, err)
+		assert.Nil(t, got)
+	}
+}
+
+func compressSet(t *testing.T, c *simple.Compressor, values []uint32) []byte {
+	output := make([]byte, c.MaxCompressedLen(len(values)))
+	n, err := c.Compress(values, output)
+	assert.Nil(t, err)
+	return output[:(n+7)/8]
+}
+
+func TestCompressed(t *testing.T) {
+	ops := []struct {
+		op   func(d *simple.Decompressor, c *simple.Compressor, a, b []byte) ([]byte, error)
+		keep func(inA, inB bool) bool
+	}{
+		{Intersect, both},
+		{Union, either},
+		{Difference, onlyA},
+	}
+
+	for _, blockSize := range []int{0, 16} {
+		for _, testCase := range setopsParams {
+			a := randomSet(testCase.sizeA, testCase.universe, testCase.order)
+			b := randomSet(testCase.sizeB, testCase.universe, testCase.order)
+
+			c := simple.NewCompressor(testCase.order, 32)
+			c.BlockSize = blockSize
+			d := simple.NewDecompressor(testCase.order, 32)
+			d.Blocked = blockSize > 0
+
+			blobA := compressSet(t, c, a)
+			blobB := compressSet(t, c, b)
+
+			for _, op := range ops {
+				result, err := op.op(d, c, blobA, blobB)
+				assert.Nil(t, err)
+
+				got, err := d.Decompress(result)
+				assert.Nil(t, err)
+				assert.Equal(t, reference(a, b, testCase.order, op.keep), append([]uint32{}, got...))
+			}
+		}
+	}
+}
+
+func TestCompressedReordersResult(t *testing.T) {
+	a := []uint32{1, 3, 5, 7}
+	b := []uint32{3, 4, 5}
+
+	c := simple.NewCompressor(simple.OrderAscending, 32)
+	blobA := compressSet(t, c, a)
+	blobB := compressSet(t, c, b)
+
+	d := simple.NewDecompressor(simple.OrderAscending, 32)
+	result, err := Intersect(d, simple.NewCompressor(simple.OrderDescending, 32), blobA, blobB)
+	assert.Nil(t, err)
+
+	got, err := simple.NewDecompressor(simple.OrderDescending, 32).Decompress(result)
+	assert.Nil(t, err)
+	assert.Equal(t, []uint32{5, 3}, got)
+}
+
+func TestCompressedOrderMismatch(t *testing.T) {
+	asc := simple.NewCompressor(simple.OrderAscending, 32)
+	asc.Framed = true
+	desc := simple.NewCompressor(simple.OrderDescending, 32)
+	desc.Framed = true
+
+	blobA := compressSet(t, asc, []uint32{1, 2, 3})
+	blobB := compressSet(t, desc, []uint32{3, 2, 1})
+
+	d := simple.NewFramedDecompressor()
+	_, err := Intersect(d, asc, blobA, blobB)
+	assert.Equal(t, ErrOrderMismatch, err)
+}
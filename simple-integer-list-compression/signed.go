@@ -0,0 +1,71 @@
+package simple
+
+// Signed lists are mapped onto unsigned ones by flipping the sign bit, which
+// keeps the order of the values, and then always encoded in frame-of-reference
+// mode. Zigzag encoding is not used since it does not preserve the order that
+// the width chain relies on.
+type Signed interface {
+	~int32 | ~int64
+}
+
+func signBit[T Unsigned]() T {
+	return 1 << uint(wordSize[T]()-1)
+}
+
+func compressSigned[S Signed, T Unsigned](c *GenericCompressor[T], input []S, output []byte) (int, error) {
+	values := make([]T, len(input))
+	for i, v := range input {
+		values[i] = T(v) ^ signBit[T]()
+	}
+
+	c.signed = true
+	defer func() {
+		c.signed = false
+	}()
+
+	return c.Compress(values, output)
+}
+
+func decompressSigned[S Signed, T Unsigned](d *GenericDecompressor[T], input []byte) ([]S, error) {
+	d.signed = true
+	defer func() {
+		d.signed = false
+	}()
+
+	values, err := d.Decompress(input)
+	if err != nil {
+		return nil, err
+	}
+
+	output := make([]S, len(values))
+	for i, v := range values {
+		output[i] = S(v ^ signBit[T]())
+	}
+	return output, nil
+}
+
+func CompressInt32(c *Compressor, input []int32, output []byte) (int, error) {
+	return compressSigned(c, input, output)
+}
+
+func DecompressInt32(d *Decompressor, input []byte) ([]int32, error) {
+	return decompressSigned[int32](d, input)
+}
+
+func CompressInt64(c *Compressor64, input []int64, output []byte) (int, error) {
+	return compressSigned(c, input, output)
+}
+
+func DecompressInt64(d *Decompressor64, input []byte) ([]int64, error) {
+	return decompressSigned[int64](d, input)
+}
+
+// MaxCompressedSignedLen is like MaxCompressedLen but for the output of
+// CompressInt32 and CompressInt64, which also holds the base value.
+func (c *GenericCompressor[T]) MaxCompressedSignedLen(n int) int {
+	size := c.MaxCompressedLen(n)
+	if size == 0 || c.FrameOfReference {
+		return size
+	}
+	return size + wordSize[T]()/8
+}
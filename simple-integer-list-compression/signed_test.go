@@ -0,0 +1,143 @@
+package simple
+
+import (
+	"math"
+	"math/rand"
+	"sort"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func randomSortedInt32Slice(n int, lo, hi int64, order int) []int32 {
+	s := make([]int32, n)
+	for i := range s {
+		s[i] = int32(lo + rand.Int63n(hi-lo+1))
+	}
+	sort.Slice(s, func(i, j int) bool {
+		if order == OrderAscending {
+			return s[i] < s[j]
+		}
+		return s[i] > s[j]
+	})
+	return s
+}
+
+func TestCompressAndDecompressInt32(t *testing.T) {
+	params := []struct {
+		order     int
+		inputSize int
+		lo        int64
+		hi        int64
+		delta     bool
+		blockSize int
+		framed    bool
+	}{
+		{OrderAscending, 0, -10, 10, false, 0, false},
+		{OrderAscending, 1, -10, 10, false, 0, false},
+		{OrderAscending, 1000, -1000, 1000, false, 0, false},
+		{OrderAscending, 1000, math.MinInt32, math.MaxInt32, false, 0, false},
+		{OrderAscending, 1000, -5000, -1000, true, 0, true},
+		{OrderAscending, 1000, -5000, 5000, false, DefaultBlockSize, true},
+		{OrderDescending, 0, -10, 10, false, 0, false},
+		{OrderDescending, 1, -10, 10, false, 0, false},
+		{OrderDescending, 1000, -1000, 1000, false, 0, false},
+		{OrderDescending, 1000, math.MinInt32, math.MaxInt32, false, 0, false},
+		{OrderDescending, 1000, -5000, -1000, true, 0, true},
+		{OrderDescending, 1000, -5000, 5000, false, DefaultBlockSize, true},
+	}
+
+	for _, testCase := range params {
+		input := randomSortedInt32Slice(testCase.inputSize, testCase.lo, testCase.hi, testCase.order)
+
+		c := NewCompressor(testCase.order, 32)
+		c.Delta = testCase.delta
+		c.BlockSize = testCase.blockSize
+		c.Framed = testCase.framed
+		compOutput := make([]byte, c.MaxCompressedSignedLen(len(input)))
+		_, err := CompressInt32(c, input, compOutput)
+		assert.Nil(t, err)
+
+		var d *Decompressor
+		if testCase.framed {
+			d = NewFramedDecompressor()
+		} else {
+			d = NewDecompressor(testCase.order, 32)
+			d.Delta = testCase.delta
+			d.Blocked = testCase.blockSize > 0
+		}
+		output, err := DecompressInt32(d, compOutput)
+		assert.Nil(t, err)
+		assert.Equal(t, input, output)
+
+		if testCase.framed {
+			_, err = d.Decompress(compOutput)
+			assert.Equal(t, ErrSignednessMismatch, err)
+		}
+	}
+}
+
+func TestCompressAndDecompressInt64(t *testing.T) {
+	for _, order := range []int{OrderAscending, OrderDescending} {
+		input := make([]int64, 1000)
+		for i := range input {
+			input[i] = rand.Int63() - math.MaxInt64/2
+		}
+		sort.Slice(input, func(i, j int) bool {
+			if order == OrderAscending {
+				return input[i] < input[j]
+			}
+			return input[i] > input[j]
+		})
+
+		c := NewCompressor64(order, 32)
+		compOutput := make([]byte, c.MaxCompressedSignedLen(len(input)))
+		_, err := CompressInt64(c, input, compOutput)
+		assert.Nil(t, err)
+
+		d := NewDecompressor64(order, 32)
+		output, err := DecompressInt64(d, compOutput)
+		assert.Nil(t, err)
+		assert.Equal(t, input, output)
+	}
+}
+
+func TestCompressInt32IsSmallerThanReinterpretedBits(t *testing.T) {
+	input := randomSortedInt32Slice(1000, -1000, 1000, OrderAscending)
+
+	c := NewCompressor(OrderAscending, 32)
+	n, err := CompressInt32(c, input, make([]byte, c.MaxCompressedSignedLen(len(input))))
+	assert.Nil(t, err)
+
+	reinterpreted := make([]uint32, len(input))
+	for i, v := range input {
+		reinterpreted[i] = uint32(v)
+	}
+	sort.Slice(reinterpreted, func(i, j int) bool { return reinterpreted[i] < reinterpreted[j] })
+	m, err := c.Compress(reinterpreted, make([]byte, c.MaxCompressedLen(len(input))))
+	assert.Nil(t, err)
+
+	assert.Less(t, n, m*2/3)
+}
+
+func TestBlockList_SignedBase(t *testing.T) {
+	input := randomSortedInt32Slice(1000, -5000, 5000, OrderAscending)
+
+	c := NewCompressor(OrderAscending, 32)
+	c.BlockSize = DefaultBlockSize
+	compOutput := make([]byte, c.MaxCompressedSignedLen(len(input)))
+	_, err := CompressInt32(c, input, compOutput)
+	assert.Nil(t, err)
+
+	d := NewDecompressor(OrderAscending, 32)
+	d.Blocked = true
+	d.signed = true
+	l, err := d.Open(compOutput)
+	assert.Nil(t, err)
+
+	for i, v := range input {
+		got, err := l.Get(i)
+		assert.Nil(t, err)
+		assert.Equal(t, uint32(v)^signBit[uint32](), got)
+	}
+}
@@ -0,0 +1,130 @@
+package slice
+
+import (
+	"math"
+	"math/rand"
+)
+
+// The generators below return sorted lists of n values following
+// distributions closer to real posting lists than uniform values. All of
+// them draw from r, so that lists can be reproduced from a seed.
+
+// addGap adds gap to v, saturating at the largest uint32.
+func addGap(v uint32, gap uint64) uint32 {
+	if uint64(v)+gap > math.MaxUint32 {
+		return math.MaxUint32
+	}
+	return v + uint32(gap)
+}
+
+// geometricGap returns a gap drawn from a geometric distribution with the
+// given mean.
+func geometricGap(r *rand.Rand, mean float64) uint64 {
+	return uint64(r.ExpFloat64() * mean)
+}
+
+// ZipfUint32Slice returns values in [0, max] drawn from a Zipf distribution
+// with exponent s, so that small values are much more frequent than large
+// ones. It panics if s is not larger than 1.
+func ZipfUint32Slice(r *rand.Rand, n int, s float64, max uint32) []uint32 {
+	if !(s > 1) {
+		panic("slice: Zipf exponent not larger than 1")
+	}
+
+	z := rand.NewZipf(r, s, 1, uint64(max))
+	out := make([]uint32, n)
+	for i := range out {
+		out[i] = uint32(z.Uint64())
+	}
+	return SortAscUint32Slice(out)
+}
+
+// ClusteredUint32Slice returns values grouped in clusters of clusterSize
+// values each, spread over spread consecutive integers starting at a
+// uniformly random point. It panics if clusterSize or spread is not
+// positive.
+func ClusteredUint32Slice(r *rand.Rand, n, clusterSize int, spread uint32) []uint32 {
+	if clusterSize <= 0 {
+		panic("slice: cluster size not positive")
+	}
+	if spread == 0 {
+		panic("slice: cluster spread not positive")
+	}
+
+	out := make([]uint32, 0, n)
+	for len(out) < n {
+		start := r.Uint32()
+		for i := 0; i < clusterSize && len(out) < n; i++ {
+			out = append(out, addGap(start, uint64(r.Int63n(int64(spread)))))
+		}
+	}
+	return SortAscUint32Slice(out)
+}
+
+// DenseRangeUint32Slice returns uniform values taken from a range of about
+// n/density integers starting at a random point, so that density is the
+// expected fraction of the range covered. It panics if density is not
+// positive.
+func DenseRangeUint32Slice(r *rand.Rand, n int, density float64) []uint32 {
+	if !(density > 0) {
+		panic("slice: density not positive")
+	}
+
+	size := uint64(math.Ceil(float64(n) / density))
+	size = min(max(size, 1), math.MaxUint32+1)
+	start := uint64(r.Int63n(int64(math.MaxUint32 + 1 - size + 1)))
+
+	out := make([]uint32, n)
+	for i := range out {
+		out[i] = uint32(start + uint64(r.Int63n(int64(size))))
+	}
+	return SortAscUint32Slice(out)
+}
+
+// GeometricGapUint32Slice returns values whose gaps follow a geometric
+// distribution with the given mean. It panics if mean is not positive.
+func GeometricGapUint32Slice(r *rand.Rand, n int, mean float64) []uint32 {
+	if !(mean > 0) {
+		panic("slice: mean gap not positive")
+	}
+
+	out := make([]uint32, n)
+	var v uint32
+	for i := range out {
+		v = addGap(v, geometricGap(r, mean))
+		out[i] = v
+	}
+	return out
+}
+
+// MarkovGapUint32Slice returns values whose gaps come from a two-state
+// Markov chain: in the dense state gaps are geometric with mean denseGap,
+// in the sparse state with mean sparseGap, and the chain switches state
+// with probability switchProb after every value. It models bursts of close
+// values separated by long stretches. It panics if denseGap or sparseGap
+// is not positive.
+func MarkovGapUint32Slice(r *rand.Rand, n int, denseGap, sparseGap, switchProb float64) []uint32 {
+	if !(denseGap > 0) {
+		panic("slice: dense gap not positive")
+	}
+	if !(sparseGap > 0) {
+		panic("slice: sparse gap not positive")
+	}
+
+	out := make([]uint32, n)
+	var v uint32
+	dense := true
+	for i := range out {
+		mean := sparseGap
+		if dense {
+			mean = denseGap
+		}
+		v = addGap(v, geometricGap(r, mean))
+		out[i] = v
+
+		if r.Float64() < switchProb {
+			dense = !dense
+		}
+	}
+	return out
+}
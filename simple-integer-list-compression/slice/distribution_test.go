@@ -0,0 +1,83 @@
+package slice
+
+import (
+	"math"
+	"math/rand"
+	"slices"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDistributions(t *testing.T) {
+	params := []struct {
+		name     string
+		generate func(r *rand.Rand, n int) []uint32
+	}{
+		{"zipf", func(r *rand.Rand, n int) []uint32 { return ZipfUint32Slice(r, n, 1.5, 1<<20) }},
+		{"zipf max", func(r *rand.Rand, n int) []uint32 { return ZipfUint32Slice(r, n, 1.01, 1<<32-1) }},
+		{"clustered", func(r *rand.Rand, n int) []uint32 { return ClusteredUint32Slice(r, n, 64, 1000) }},
+		{"clustered single", func(r *rand.Rand, n int) []uint32 { return ClusteredUint32Slice(r, n, 1, 1) }},
+		{"dense", func(r *rand.Rand, n int) []uint32 { return DenseRangeUint32Slice(r, n, 0.5) }},
+		{"dense full", func(r *rand.Rand, n int) []uint32 { return DenseRangeUint32Slice(r, n, 1) }},
+		{"dense sparse", func(r *rand.Rand, n int) []uint32 { return DenseRangeUint32Slice(r, n, 1e-9) }},
+		{"geometric", func(r *rand.Rand, n int) []uint32 { return GeometricGapUint32Slice(r, n, 100) }},
+		{"geometric saturated", func(r *rand.Rand, n int) []uint32 { return GeometricGapUint32Slice(r, n, 1e9) }},
+		{"markov", func(r *rand.Rand, n int) []uint32 { return MarkovGapUint32Slice(r, n, 2, 10000, 0.05) }},
+	}
+
+	for _, testCase := range params {
+		for _, n := range []int{0, 1, 1000} {
+			s := testCase.generate(rand.New(rand.NewSource(1)), n)
+			assert.Equal(t, n, len(s), testCase.name)
+			assert.True(t, slices.IsSorted(s), testCase.name)
+
+			again := testCase.generate(rand.New(rand.NewSource(1)), n)
+			assert.Equal(t, s, again, testCase.name)
+		}
+	}
+}
+
+func TestZipfUint32Slice_Max(t *testing.T) {
+	s := ZipfUint32Slice(rand.New(rand.NewSource(1)), 1000, 1.1, 100)
+	assert.LessOrEqual(t, s[len(s)-1], uint32(100))
+}
+
+func TestClusteredUint32Slice_Spread(t *testing.T) {
+	// a single cluster covers spread consecutive integers at most
+	s := ClusteredUint32Slice(rand.New(rand.NewSource(1)), 100, 100, 10)
+	assert.Less(t, s[len(s)-1]-s[0], uint32(10))
+}
+
+func TestDenseRangeUint32Slice_Range(t *testing.T) {
+	s := DenseRangeUint32Slice(rand.New(rand.NewSource(1)), 1000, 0.25)
+	assert.Less(t, s[len(s)-1]-s[0], uint32(4000))
+}
+
+func TestDistributionsPanic(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+
+	params := []struct {
+		generate func()
+		expected string
+	}{
+		{func() { ZipfUint32Slice(r, 10, 1, 100) }, "slice: Zipf exponent not larger than 1"},
+		{func() { ZipfUint32Slice(r, 10, 0.5, 100) }, "slice: Zipf exponent not larger than 1"},
+		{func() { ClusteredUint32Slice(r, 10, 0, 100) }, "slice: cluster size not positive"},
+		{func() { ClusteredUint32Slice(r, 10, -1, 100) }, "slice: cluster size not positive"},
+		{func() { ClusteredUint32Slice(r, 10, 5, 0) }, "slice: cluster spread not positive"},
+		{func() { DenseRangeUint32Slice(r, 10, 0) }, "slice: density not positive"},
+		{func() { DenseRangeUint32Slice(r, 10, -0.5) }, "slice: density not positive"},
+		{func() { GeometricGapUint32Slice(r, 10, 0) }, "slice: mean gap not positive"},
+		{func() { GeometricGapUint32Slice(r, 10, -100) }, "slice: mean gap not positive"},
+		{func() { GeometricGapUint32Slice(r, 10, math.NaN()) }, "slice: mean gap not positive"},
+		{func() { MarkovGapUint32Slice(r, 10, -2, 10000, 0.05) }, "slice: dense gap not positive"},
+		{func() { MarkovGapUint32Slice(r, 10, math.NaN(), 10000, 0.05) }, "slice: dense gap not positive"},
+		{func() { MarkovGapUint32Slice(r, 10, 2, 0, 0.05) }, "slice: sparse gap not positive"},
+		{func() { MarkovGapUint32Slice(r, 10, 2, math.NaN(), 0.05) }, "slice: sparse gap not positive"},
+	}
+
+	for _, testCase := range params {
+		assert.PanicsWithValue(t, testCase.expected, testCase.generate)
+	}
+}
@@ -0,0 +1,51 @@
+package slice
+
+import (
+	"math/rand"
+)
+
+// RandomSortedSet returns n distinct values drawn uniformly from
+// [0, universe), in ascending order. It panics if n is larger than universe
+// or universe is larger than 2^32.
+//
+// Sparse sets are drawn with Floyd's algorithm, which takes O(n) time and
+// space whatever the universe. Dense ones are drawn with selection sampling,
+// a single pass over the universe that yields the values already sorted.
+func RandomSortedSet(r *rand.Rand, n int, universe uint64) []uint32 {
+	if universe > 1<<32 {
+		panic("slice: universe larger than 2^32")
+	}
+	if uint64(n) > universe {
+		panic("slice: n larger than universe")
+	}
+
+	if universe <= 4*uint64(n) {
+		return selectionSample(r, n, universe)
+	}
+	return floydSample(r, n, universe)
+}
+
+func floydSample(r *rand.Rand, n int, universe uint64) []uint32 {
+	set := make(map[uint32]struct{}, n)
+	out := make([]uint32, 0, n)
+	for j := universe - uint64(n); j < universe; j++ {
+		v := uint32(r.Int63n(int64(j) + 1))
+		if _, ok := set[v]; ok {
+			v = uint32(j)
+		}
+		set[v] = struct{}{}
+		out = append(out, v)
+	}
+	return SortAscUint32Slice(out)
+}
+
+func selectionSample(r *rand.Rand, n int, universe uint64) []uint32 {
+	out := make([]uint32, 0, n)
+	for v := uint64(0); v < universe && len(out) < n; v++ {
+		// select v with probability (values left) / (candidates left)
+		if uint64(r.Int63n(int64(universe-v))) < uint64(n-len(out)) {
+			out = append(out, uint32(v))
+		}
+	}
+	return out
+}
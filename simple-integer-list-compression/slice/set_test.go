@@ -0,0 +1,40 @@
+package slice
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRandomSortedSet(t *testing.T) {
+	params := []struct {
+		n        int
+		universe uint64
+	}{
+		{0, 0},
+		{0, 1000},
+		// Floyd's algorithm
+		{1, 1000},
+		{100, 1000},
+		{249, 1000},
+		{1000, 1 << 32},
+		// selection sampling
+		{250, 1000},
+		{900, 1000},
+		{1000, 1000},
+		{1, 1},
+	}
+
+	r := rand.New(rand.NewSource(1))
+	for _, testCase := range params {
+		set := RandomSortedSet(r, testCase.n, testCase.universe)
+		assert.Equal(t, testCase.n, len(set))
+		for i, v := range set {
+			assert.Less(t, uint64(v), testCase.universe)
+			if i > 0 {
+				assert.Less(t, set[i-1], v)
+			}
+		}
+	}
+}
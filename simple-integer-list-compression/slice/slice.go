@@ -22,6 +22,40 @@ func RandomUint32Slice(n int) []uint32 {
 	return s
 }
 
+func RandomUint64Slice(n int) []uint64 {
+	s := make([]uint64, n)
+	for i := 0; i < n; i++ {
+		s[i] = rand.Uint64()
+	}
+	return s
+}
+
+// RandomInt31SliceFrom is like RandomInt31Slice but draws the values from
+// r, so that the slice can be reproduced from a seed.
+func RandomInt31SliceFrom(r *rand.Rand, n int) []int32 {
+	s := make([]int32, n)
+	for i := 0; i < n; i++ {
+		s[i] = r.Int31()
+	}
+	return s
+}
+
+func RandomUint32SliceFrom(r *rand.Rand, n int) []uint32 {
+	s := make([]uint32, n)
+	for i := 0; i < n; i++ {
+		s[i] = r.Uint32()
+	}
+	return s
+}
+
+func RandomUint64SliceFrom(r *rand.Rand, n int) []uint64 {
+	s := make([]uint64, n)
+	for i := 0; i < n; i++ {
+		s[i] = r.Uint64()
+	}
+	return s
+}
+
 func Int32ToUint32Slice(s []int32) []uint32 {
 	n := len(s)
 	out := make([]uint32, n)
@@ -58,6 +92,15 @@ func Uint32ToByteSlice(s []uint32) []byte {
 	return out
 }
 
+func ByteToUint32Slice(b []byte) []uint32 {
+	n := len(b) / 4
+	out := make([]uint32, n)
+	for i := 0; i < n; i++ {
+		out[i] = binary.LittleEndian.Uint32(b[i*4:])
+	}
+	return out
+}
+
 func SortAscUint32Slice(s []uint32) []uint32 {
 	sort.Slice(s, func(i, j int) bool {
 		return s[i] < s[j]
@@ -72,6 +115,20 @@ func SortDescUint32Slice(s []uint32) []uint32 {
 	return s
 }
 
+func SortAscUint64Slice(s []uint64) []uint64 {
+	sort.Slice(s, func(i, j int) bool {
+		return s[i] < s[j]
+	})
+	return s
+}
+
+func SortDescUint64Slice(s []uint64) []uint64 {
+	sort.Slice(s, func(i, j int) bool {
+		return s[i] > s[j]
+	})
+	return s
+}
+
 func SortAscInt32Slice(s []int32) []int32 {
 	sort.Slice(s, func(i, j int) bool {
 		return s[i] < s[j]
@@ -0,0 +1,293 @@
+package simple
+
+import (
+	"encoding/binary"
+	"io"
+
+	"github.com/vteromero/bitstream"
+)
+
+// A stream is a sequence of chunks, each one laid out as:
+//
+//	count (4 bytes, LE) | payload length in bytes (4 bytes, LE) | payload
+//
+// where payload holds count values encoded like the body of an unframed
+// blob, with the width chain restarting at 32 bits. A chunk with count 0
+// marks the end of the stream.
+const (
+	DefaultChunkSize    = 4096
+	DefaultMaxChunkSize = 1 << 20
+	chunkHeaderLen      = 8
+)
+
+// Writer encodes a stream in chunks of ChunkSize values. ChunkSize is capped
+// at DefaultMaxChunkSize, so that a Reader with the default limit accepts
+// every stream written.
+type Writer struct {
+	ListOrder int
+	ChunkSize int
+	w         io.Writer
+	values    []uint32
+	buf       []byte
+	writer    *bitstream.Writer
+	count     int
+	width     int
+	err       error
+}
+
+func NewWriter(w io.Writer, order int) *Writer {
+	return &Writer{
+		ListOrder: order,
+		ChunkSize: DefaultChunkSize,
+		w:         w,
+	}
+}
+
+func (w *Writer) chunkSize() int {
+	if w.ChunkSize <= 0 {
+		return DefaultChunkSize
+	}
+	return min(w.ChunkSize, DefaultMaxChunkSize)
+}
+
+func (w *Writer) startChunk() {
+	size := chunkHeaderLen + 4*w.chunkSize()
+	if len(w.buf) < size {
+		w.buf = make([]byte, size)
+	} else {
+		clear(w.buf)
+	}
+	w.writer = bitstream.NewWriter(w.buf[chunkHeaderLen:])
+	w.count = 0
+	w.width = 32
+}
+
+func (w *Writer) writeValueDesc(value uint32) error {
+	if w.writer == nil {
+		w.startChunk()
+	}
+
+	if err := w.writer.Write(uint64(value), w.width); err != nil {
+		return err
+	}
+	w.width = bitsLen(value)
+	w.count++
+
+	if w.count == w.chunkSize() {
+		return w.flushChunk()
+	}
+	return nil
+}
+
+func (w *Writer) writeValueAsc(value uint32) error {
+	w.values = append(w.values, value)
+	if len(w.values) == w.chunkSize() {
+		return w.flushChunk()
+	}
+	return nil
+}
+
+func (w *Writer) encodeAsc() error {
+	w.startChunk()
+	c := &Compressor{
+		ListOrder: OrderAscending,
+		writer:    w.writer,
+	}
+	if err := c.writeValuesAsc(w.values); err != nil {
+		return err
+	}
+	w.count = len(w.values)
+	w.values = w.values[:0]
+	return nil
+}
+
+func (w *Writer) flushChunk() error {
+	if w.ListOrder == OrderAscending && len(w.values) > 0 {
+		if err := w.encodeAsc(); err != nil {
+			return err
+		}
+	}
+
+	if w.writer == nil || w.count == 0 {
+		return nil
+	}
+
+	payloadLen := sizeInBytes(w.writer.Offset())
+	binary.LittleEndian.PutUint32(w.buf[0:], uint32(w.count))
+	binary.LittleEndian.PutUint32(w.buf[4:], uint32(payloadLen))
+	w.writer = nil
+
+	_, err := w.w.Write(w.buf[:chunkHeaderLen+payloadLen])
+	return err
+}
+
+// Write encodes values, which must continue the order of the values
+// previously written. It returns the number of values consumed.
+func (w *Writer) Write(values []uint32) (int, error) {
+	if w.err != nil {
+		return 0, w.err
+	}
+
+	for i, value := range values {
+		if w.ListOrder == OrderAscending {
+			w.err = w.writeValueAsc(value)
+		} else {
+			w.err = w.writeValueDesc(value)
+		}
+		if w.err != nil {
+			return i, w.err
+		}
+	}
+
+	return len(values), nil
+}
+
+// Flush writes any buffered values to the underlying io.Writer as a
+// complete chunk.
+func (w *Writer) Flush() error {
+	if w.err != nil {
+		return w.err
+	}
+	w.err = w.flushChunk()
+	return w.err
+}
+
+// Close flushes any buffered values and writes the end-of-stream marker.
+// It does not close the underlying io.Writer.
+func (w *Writer) Close() error {
+	if err := w.Flush(); err != nil {
+		return err
+	}
+
+	var trailer [chunkHeaderLen]byte
+	if _, w.err = w.w.Write(trailer[:]); w.err != nil {
+		return w.err
+	}
+
+	w.err = ErrWriterClosed
+	return nil
+}
+
+// Reader decodes a stream. Chunks holding more than MaxChunkSize values are
+// rejected before anything is allocated for them; zero means no limit.
+type Reader struct {
+	ListOrder    int
+	MaxChunkSize int
+	r            io.Reader
+	header       [chunkHeaderLen]byte
+	buf          []byte
+	values       []uint32
+	reader       *bitstream.Reader
+	remaining    int
+	width        int
+	err          error
+}
+
+func NewReader(r io.Reader, order int) *Reader {
+	return &Reader{
+		ListOrder:    order,
+		MaxChunkSize: DefaultMaxChunkSize,
+		r:            r,
+	}
+}
+
+func (r *Reader) readChunk() error {
+	if _, err := io.ReadFull(r.r, r.header[:]); err != nil {
+		if err == io.EOF {
+			return io.ErrUnexpectedEOF
+		}
+		return err
+	}
+
+	count := int(binary.LittleEndian.Uint32(r.header[0:]))
+	payloadLen := int(binary.LittleEndian.Uint32(r.header[4:]))
+
+	if count == 0 {
+		return io.EOF
+	}
+
+	if r.MaxChunkSize > 0 && count > r.MaxChunkSize {
+		return ErrChunkTooLarge
+	}
+
+	if payloadLen > 4*count || payloadLen < sizeInBytes(count) {
+		return ErrInvalidChunk
+	}
+
+	if cap(r.buf) < payloadLen {
+		r.buf = make([]byte, payloadLen)
+	}
+	r.buf = r.buf[:payloadLen]
+
+	if _, err := io.ReadFull(r.r, r.buf); err != nil {
+		if err == io.EOF {
+			return io.ErrUnexpectedEOF
+		}
+		return err
+	}
+
+	r.reader = bitstream.NewReader(r.buf)
+	r.remaining = count
+	r.width = 32
+
+	if r.ListOrder == OrderAscending {
+		return r.decodeAsc(count)
+	}
+	return nil
+}
+
+func (r *Reader) decodeAsc(count int) error {
+	if cap(r.values) < count {
+		r.values = make([]uint32, count)
+	}
+	r.values = r.values[:count]
+
+	d := &Decompressor{
+		ListOrder: OrderAscending,
+		reader:    r.reader,
+	}
+	_, err := d.readValuesAsc(r.values)
+	return err
+}
+
+func (r *Reader) readValue() (uint32, error) {
+	if r.ListOrder == OrderAscending {
+		value := r.values[len(r.values)-r.remaining]
+		r.remaining--
+		return value, nil
+	}
+
+	v, err := r.reader.Read(r.width)
+	if err != nil {
+		return 0, ErrTruncatedInput
+	}
+	r.width = bitsLen(uint32(v))
+	r.remaining--
+	return uint32(v), nil
+}
+
+// Read decodes up to len(values) values into values and returns the number
+// of values read. At the end of the stream it returns io.EOF.
+func (r *Reader) Read(values []uint32) (int, error) {
+	n := 0
+	for n < len(values) {
+		if r.err != nil {
+			return n, r.err
+		}
+
+		if r.remaining == 0 {
+			r.err = r.readChunk()
+			continue
+		}
+
+		value, err := r.readValue()
+		if err != nil {
+			r.err = err
+			continue
+		}
+
+		values[n] = value
+		n++
+	}
+	return n, nil
+}
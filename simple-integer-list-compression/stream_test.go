@@ -0,0 +1,168 @@
+package simple
+
+import (
+	"bytes"
+	"encoding/binary"
+	"io"
+	"testing"
+
+	"github.com/vteromero/playground/simple-integer-list-compression/slice"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWriterAndReader(t *testing.T) {
+	params := []struct {
+		order     int
+		chunkSize int
+		inputSize int
+		batchSize int
+	}{
+		{OrderAscending, 4, 0, 1},
+		{OrderAscending, 4, 10, 1},
+		{OrderAscending, 4, 10, 3},
+		{OrderAscending, 100, 1000, 7},
+		{OrderAscending, DefaultChunkSize, 10000, 1000},
+		{OrderDescending, 4, 0, 1},
+		{OrderDescending, 4, 10, 1},
+		{OrderDescending, 4, 10, 3},
+		{OrderDescending, 100, 1000, 7},
+		{OrderDescending, DefaultChunkSize, 10000, 1000},
+	}
+
+	for _, testCase := range params {
+		var input []uint32
+		if testCase.order == OrderAscending {
+			input = slice.SortAscUint32Slice(slice.RandomUint32Slice(testCase.inputSize))
+		} else {
+			input = slice.SortDescUint32Slice(slice.RandomUint32Slice(testCase.inputSize))
+		}
+
+		var buf bytes.Buffer
+		w := NewWriter(&buf, testCase.order)
+		w.ChunkSize = testCase.chunkSize
+		for i := 0; i < len(input); i += testCase.batchSize {
+			end := min(i+testCase.batchSize, len(input))
+			n, err := w.Write(input[i:end])
+			assert.Nil(t, err)
+			assert.Equal(t, end-i, n)
+		}
+		assert.Nil(t, w.Close())
+
+		r := NewReader(&buf, testCase.order)
+		output := make([]uint32, 0, len(input))
+		batch := make([]uint32, testCase.batchSize)
+		for {
+			n, err := r.Read(batch)
+			output = append(output, batch[:n]...)
+			if err == io.EOF {
+				break
+			}
+			assert.Nil(t, err)
+		}
+
+		assert.Equal(t, input, output)
+	}
+}
+
+func TestWriter_WriteAfterClose(t *testing.T) {
+	var buf bytes.Buffer
+	w := NewWriter(&buf, OrderDescending)
+	assert.Nil(t, w.Close())
+
+	_, err := w.Write([]uint32{1})
+	assert.Equal(t, ErrWriterClosed, err)
+}
+
+func TestReader_Read(t *testing.T) {
+	params := []struct {
+		input          []byte
+		expectedErr    error
+		expectedOutput []uint32
+	}{
+		{[]byte{0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00}, io.EOF, []uint32{}},
+		{[]byte{0x03, 0x00, 0x00, 0x00, 0x07, 0x00, 0x00, 0x00, 0xb8, 0x22, 0x00, 0x00, 0x6f, 0x40, 0x01}, io.ErrUnexpectedEOF, []uint32{8888, 111, 5}},
+		{[]byte{0x03, 0x00, 0x00, 0x00, 0x07, 0x00, 0x00, 0x00, 0xb8, 0x22}, io.ErrUnexpectedEOF, []uint32{}},
+		{[]byte{0x03, 0x00, 0x00, 0x00, 0xff, 0x00, 0x00, 0x00}, ErrInvalidChunk, []uint32{}},
+		{[]byte{0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff}, ErrChunkTooLarge, []uint32{}},
+		{[]byte{0x03, 0x00}, io.ErrUnexpectedEOF, []uint32{}},
+	}
+
+	for _, testCase := range params {
+		r := NewReader(bytes.NewReader(testCase.input), OrderDescending)
+		output := make([]uint32, 10)
+		n, err := r.Read(output)
+		assert.Equal(t, testCase.expectedErr, err)
+		assert.Equal(t, testCase.expectedOutput, output[:n])
+	}
+}
+
+func TestReader_ReadTruncatedPayload(t *testing.T) {
+	// a chunk of 3 values whose payload is too short for the first one
+	input := []byte{0x03, 0x00, 0x00, 0x00, 0x01, 0x00, 0x00, 0x00, 0xb8}
+
+	for _, order := range []int{OrderAscending, OrderDescending} {
+		r := NewReader(bytes.NewReader(input), order)
+		n, err := r.Read(make([]uint32, 10))
+		assert.Equal(t, ErrTruncatedInput, err)
+		assert.Equal(t, 0, n)
+	}
+}
+
+func TestReader_MaxChunkSize(t *testing.T) {
+	input := slice.SortDescUint32Slice(slice.RandomUint32Slice(100))
+
+	var buf bytes.Buffer
+	w := NewWriter(&buf, OrderDescending)
+	w.ChunkSize = 100
+	_, err := w.Write(input)
+	assert.Nil(t, err)
+	assert.Nil(t, w.Close())
+
+	r := NewReader(bytes.NewReader(buf.Bytes()), OrderDescending)
+	r.MaxChunkSize = 99
+	_, err = r.Read(make([]uint32, 100))
+	assert.Equal(t, ErrChunkTooLarge, err)
+
+	r = NewReader(bytes.NewReader(buf.Bytes()), OrderDescending)
+	r.MaxChunkSize = 0
+	output := make([]uint32, 100)
+	n, err := r.Read(output)
+	assert.Nil(t, err)
+	assert.Equal(t, input, output[:n])
+}
+
+func TestWriter_ChunkSizeAboveMax(t *testing.T) {
+	for _, order := range []int{OrderAscending, OrderDescending} {
+		var input []uint32
+		if order == OrderAscending {
+			input = slice.SortAscUint32Slice(slice.RandomUint32Slice(DefaultMaxChunkSize + 100))
+		} else {
+			input = slice.SortDescUint32Slice(slice.RandomUint32Slice(DefaultMaxChunkSize + 100))
+		}
+
+		var buf bytes.Buffer
+		w := NewWriter(&buf, order)
+		w.ChunkSize = 2 * DefaultMaxChunkSize
+		_, err := w.Write(input)
+		assert.Nil(t, err)
+		assert.Nil(t, w.Close())
+
+		// the first chunk is cut at the limit of the reader
+		assert.Equal(t, uint32(DefaultMaxChunkSize), binary.LittleEndian.Uint32(buf.Bytes()))
+
+		r := NewReader(&buf, order)
+		output := make([]uint32, 0, len(input))
+		batch := make([]uint32, 1000)
+		for {
+			n, err := r.Read(batch)
+			output = append(output, batch[:n]...)
+			if err == io.EOF {
+				break
+			}
+			if !assert.Nil(t, err) {
+				break
+			}
+		}
+		assert.Equal(t, input, output)
+	}
+}